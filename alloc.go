@@ -0,0 +1,49 @@
+package serrors
+
+// stackBufferSize is the size of the buffer on the goroutine stack that is used to capture
+// the program counters, bigger stacks use a buffer on the heap.
+const stackBufferSize = DefaultStackDepth + internalFrames
+
+// errorWithStack holds an error together with its stack and the program counters,
+// so that creating an error only needs a single allocation.
+// B is an array of program counters, different sizes are used to avoid wasting memory for
+// errors with small stacks.
+type errorWithStack[B any] struct {
+	err   Error
+	stack stack
+	pcs   B
+}
+
+// allocError returns a new Error with a stack holding a copy of the program counters.
+func allocError(pcs []uintptr, opts stackOptions) *Error {
+	//nolint:gomnd // size classes of the program counter arrays
+	switch n := len(pcs); {
+	case n == 0:
+		return &Error{}
+	case n <= 8:
+		return allocErrorWithStack(pcs, opts, func(b *[8]uintptr) []uintptr { return b[:] })
+	case n <= 16:
+		return allocErrorWithStack(pcs, opts, func(b *[16]uintptr) []uintptr { return b[:] })
+	case n <= 32:
+		return allocErrorWithStack(pcs, opts, func(b *[32]uintptr) []uintptr { return b[:] })
+	case n <= stackBufferSize:
+		return allocErrorWithStack(pcs, opts, func(b *[stackBufferSize]uintptr) []uintptr { return b[:] })
+	}
+	// the stack is bigger than the biggest size class, this only happens for a depth that is bigger
+	// than DefaultStackDepth
+	a := &errorWithStack[struct{}]{}
+	a.stack.pcs = append([]uintptr(nil), pcs...)
+	a.stack.opts = opts
+	a.err.stack = &a.stack
+	return &a.err
+}
+
+func allocErrorWithStack[B any](pcs []uintptr, opts stackOptions, slice func(*B) []uintptr) *Error {
+	a := &errorWithStack[B]{}
+	buf := slice(&a.pcs)
+	n := copy(buf, pcs)
+	a.stack.pcs = buf[:n]
+	a.stack.opts = opts
+	a.err.stack = &a.stack
+	return &a.err
+}
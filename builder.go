@@ -1,11 +1,15 @@
 package serrors
 
-import "fmt"
-
 // ErrorBuilder is a type that provides a way to build errors.
+// An ErrorBuilder is never modified after it was created, ErrorBuilder.With returns a new ErrorBuilder.
+// This makes it safe to share an ErrorBuilder between goroutines and to derive multiple
+// builders from a common one.
 type ErrorBuilder struct {
-	msg    string
-	fields map[string]any
+	fields       []Field
+	code         Code
+	stackDepth   int
+	stackSkip    int
+	withoutStack bool
 }
 
 // NewBuilder creates a new ErrorBuilder.
@@ -13,47 +17,60 @@ type ErrorBuilder struct {
 // or ErrorBuilder.Wrapf.
 func NewBuilder() *ErrorBuilder {
 	return &ErrorBuilder{
-		msg:    "",
-		fields: nil,
+		fields:       nil,
+		code:         "",
+		stackDepth:   0,
+		stackSkip:    0,
+		withoutStack: false,
 	}
 }
 
-// With adds the field key with the value to the error fields.
+// With returns a copy of the ErrorBuilder with the field key set to value.
+// The ErrorBuilder itself and errors that were already created by it are not modified.
 func (eb *ErrorBuilder) With(key string, value any) *ErrorBuilder {
-	if eb.fields == nil {
-		eb.fields = make(map[string]any)
-	}
-	eb.fields[key] = value
-	return eb
+	clone := *eb
+	clone.fields = withField(eb.fields, key, value)
+	return &clone
 }
 
 // New creates a new Error with the supplied message. The error
 // will contain all fields that were previously passed to ErrorBuilder.
 func (eb *ErrorBuilder) New(message string) *Error {
-	eb.msg = message
-	err := New(message)
-	err.fields = eb.fields
+	err := newError(eb.stackOptions(), message)
+	eb.apply(err)
 	return err
 }
 
 // Errorf creates a new Error with the supplied message formatted according to a format specifier.
+// Errors that are referenced with the %w verb will be added as causes for this error.
 // The error will contain all fields that were previously passed to ErrorBuilder.
 func (eb *ErrorBuilder) Errorf(format string, a ...any) *Error {
-	return eb.New(fmt.Sprintf(format, a...))
+	err := errorf(eb.stackOptions(), format, a)
+	eb.apply(err)
+	return err
 }
 
 // Wrap creates a new Error with the supplied message.
 // The passed in error will be added as a cause for this error.
 // The error will contain all fields that were previously passed to ErrorBuilder.
 func (eb *ErrorBuilder) Wrap(err error, message string) *Error {
-	serr := Wrap(err, message)
-	serr.fields = eb.fields
+	serr := wrap(eb.stackOptions(), err, message)
+	eb.apply(serr)
 	return serr
 }
 
 // Wrapf creates a new Error with the supplied message formatted according to a format specifier.
 // The passed in error will be added as a cause for this error.
+// Errors that are referenced with the %w verb will be added as additional causes for this error.
 // The error will contain all fields that were previously passed to ErrorBuilder.
 func (eb *ErrorBuilder) Wrapf(err error, format string, a ...any) *Error {
-	return eb.Wrap(err, fmt.Sprintf(format, a...))
+	serr := wrapf(eb.stackOptions(), err, format, a)
+	eb.apply(serr)
+	return serr
+}
+
+// apply adds the fields and the code of the ErrorBuilder to the error.
+func (eb *ErrorBuilder) apply(err *Error) {
+	err.fields = eb.fields
+	err.code = eb.code
 }
@@ -1,7 +1,10 @@
 package serrors_test
 
 import (
+	"errors"
+	"fmt"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/Eun/serrors"
@@ -42,6 +45,7 @@ func TestBuilder(t *testing.T) {
 			},
 		}
 		Equal(t, expectedFields, serrors.GetFields(err))
+		skipWithoutStack(t)
 		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
 	})
 
@@ -91,6 +95,65 @@ func TestBuilder(t *testing.T) {
 			},
 		}
 		Equal(t, expectedFields, serrors.GetFields(err))
+		skipWithoutStack(t)
 		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
 	})
 }
+
+func TestBuilder_CopyOnWrite(t *testing.T) {
+	t.Run("With does not modify the builder", func(t *testing.T) {
+		base := serrors.NewBuilder().With("key1", "value1")
+		derived := base.With("key2", "value2")
+
+		Equal(t, map[string]any{"key1": "value1"}, serrors.GetFields(base.New("some error")))
+		Equal(t, map[string]any{"key1": "value1", "key2": "value2"}, serrors.GetFields(derived.New("some error")))
+	})
+
+	t.Run("With does not modify errors that were already created", func(t *testing.T) {
+		errorBuilder := serrors.NewBuilder().With("key1", "value1")
+		err1 := errorBuilder.New("error 1")
+		errorBuilder = errorBuilder.With("key1", "overwritten").With("key2", "value2")
+		err2 := errorBuilder.New("error 2")
+
+		Equal(t, map[string]any{"key1": "value1"}, serrors.GetFields(err1))
+		Equal(t, map[string]any{"key1": "overwritten", "key2": "value2"}, serrors.GetFields(err2))
+	})
+
+	t.Run("Error.With does not modify other errors of the builder", func(t *testing.T) {
+		errorBuilder := serrors.NewBuilder().With("key1", "value1")
+		err1 := errorBuilder.New("error 1")
+		err2 := errorBuilder.Wrap(errors.New("cause"), "error 2")
+		err3 := err1.With("key2", "value2")
+		err4 := err2.With("key1", "overwritten")
+
+		Equal(t, map[string]any{"key1": "value1"}, serrors.GetFields(err1))
+		Equal(t, map[string]any{"key1": "value1"}, serrors.GetFields(err2))
+		Equal(t, map[string]any{"key1": "value1", "key2": "value2"}, serrors.GetFields(err3))
+		Equal(t, map[string]any{"key1": "overwritten"}, serrors.GetFields(err4))
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		errorBuilder := serrors.NewBuilder().With("key", "value")
+
+		const workers = 16
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		results := make([]error, workers)
+		for i := 0; i < workers; i++ {
+			go func(i int) {
+				defer wg.Done()
+				results[i] = errorBuilder.
+					With("worker", i).
+					Errorf("error %d", i).
+					With("index", i)
+				_ = errorBuilder.New("some error").With("key", i)
+			}(i)
+		}
+		wg.Wait()
+
+		for i, err := range results {
+			Equal(t, fmt.Sprintf("error %d", i), err.Error())
+			Equal(t, map[string]any{"key": "value", "worker": i, "index": i}, serrors.GetFields(err))
+		}
+	})
+}
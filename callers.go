@@ -0,0 +1,65 @@
+package serrors
+
+import (
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Callers returns the program counters of the stack of the error, like runtime.Callers would return
+// them in the function that created the error.
+// Frames of this package, skipped frames (see SetStackSkip) and frames of helper functions
+// (see Helper) are not included, frame filters are not applied.
+// It returns nil if the error has no captured stack, e.g. when it was decoded from json.
+func (e *Error) Callers() []uintptr {
+	if e.stack == nil {
+		return nil
+	}
+	return e.stack.callers()
+}
+
+// StackTrace returns the stack of the error as a github.com/pkg/errors stack trace,
+// so the error can be used by tools that support errors created by github.com/pkg/errors.
+// It contains the same frames as Callers.
+func (e *Error) StackTrace() pkgerrors.StackTrace {
+	pcs := e.Callers()
+	if pcs == nil {
+		return nil
+	}
+	stackTrace := make(pkgerrors.StackTrace, len(pcs))
+	for i, pc := range pcs {
+		stackTrace[i] = pkgerrors.Frame(pc)
+	}
+	return stackTrace
+}
+
+// callers returns the program counters without the leading frames of this package,
+// skipped frames and helper frames.
+func (s *stack) callers() []uintptr {
+	skip := s.opts.skip
+	skipHelpers := true
+	start := len(s.pcs)
+	for i, pc := range s.pcs {
+		keep := false
+		for _, frame := range stackCache.framesForPC(pc) {
+			switch {
+			case frame.Func == "" || isInternalFunc(frame.Func):
+			case skip > 0:
+				skip--
+			case skipHelpers && isHelper(frame.Func):
+			default:
+				keep = true
+			}
+		}
+		if keep {
+			start = i
+			break
+		}
+	}
+	pcs := s.pcs[start:]
+	if s.opts.depth > 0 && len(pcs) > s.opts.depth {
+		pcs = pcs[:s.opts.depth]
+	}
+	if len(pcs) == 0 {
+		return nil
+	}
+	return append([]uintptr(nil), pcs...)
+}
@@ -0,0 +1,69 @@
+//go:build !serrors_without_stack
+// +build !serrors_without_stack
+
+package serrors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/Eun/serrors"
+)
+
+// make sure we implement the interfaces of github.com/pkg/errors.
+var _ interface{ StackTrace() pkgerrors.StackTrace } = &serrors.Error{}
+
+func TestError_Callers(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	firstFrame := func(pcs []uintptr) serrors.StackFrame {
+		frame, _ := runtime.CallersFrames(pcs).Next()
+		return serrors.StackFrame{File: frame.File, Func: frame.Function, Line: frame.Line}
+	}
+
+	t.Run("Callers", func(t *testing.T) {
+		err := serrors.New("some error") // [TestError_Callers00]
+		Equal(t, buildStackFrameFromMarker(t, filename, "TestError_Callers00"), firstFrame(err.Callers()))
+	})
+
+	t.Run("Helper", func(t *testing.T) {
+		err := newErrorInHelper() // [TestError_Callers01]
+		Equal(t, buildStackFrameFromMarker(t, filename, "TestError_Callers01"), firstFrame(err.Callers()))
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		err := serrors.NewBuilder().WithStackDepth(1).New("some error")
+		Equal(t, 1, len(err.Callers()))
+	})
+
+	t.Run("StackTrace", func(t *testing.T) {
+		err := serrors.New("some error") // [TestError_Callers02]
+		expected := buildStackFrameFromMarker(t, filename, "TestError_Callers02")
+		stackTrace := err.StackTrace()
+		Equal(t, len(err.Callers()), len(stackTrace))
+		Equal(t, fmt.Sprintf("%s\n\t%s:%d", expected.Func, expected.File, expected.Line), fmt.Sprintf("%+v", stackTrace[0]))
+	})
+
+	t.Run("used by third party errors", func(t *testing.T) {
+		// WithMessage does not add a stack, the stack of the cause is found using errors.As.
+		err := pkgerrors.WithMessage(serrors.New("some error"), "wrapped") // [TestError_Callers03]
+		expected := buildStackFrameFromMarker(t, filename, "TestError_Callers03")
+		var tracer interface{ StackTrace() pkgerrors.StackTrace }
+		Equal(t, true, pkgerrors.As(err, &tracer))
+		Equal(t, fmt.Sprintf("%s\n\t%s:%d", expected.Func, expected.File, expected.Line), fmt.Sprintf("%+v", tracer.StackTrace()[0]))
+	})
+
+	t.Run("no stack", func(t *testing.T) {
+		data, err := json.Marshal(serrors.New("some error"))
+		Nil(t, err)
+		var decoded serrors.Error
+		Nil(t, json.Unmarshal(data, &decoded))
+		Nil(t, decoded.Callers())
+		Nil(t, decoded.StackTrace())
+	})
+}
@@ -0,0 +1,48 @@
+package serrors
+
+// Code classifies an error, e.g. "not_found" or "conflict".
+// Codes are usually declared as constants:
+//
+//	const NotFound serrors.Code = "not_found"
+//
+// A Code implements the error interface, so it can be used as a target for errors.Is:
+// errors.Is(err, NotFound) reports whether any error in the chain carries the code.
+type Code string
+
+// Error returns the code as a string.
+func (c Code) Error() string {
+	return string(c)
+}
+
+// WithCode returns a copy of the error with the code set.
+// The error itself is not modified.
+func (e *Error) WithCode(code Code) *Error {
+	clone := *e
+	clone.code = code
+	clone.origin = e.root()
+	return &clone
+}
+
+// WithCode returns a copy of the ErrorBuilder with the code set.
+// All errors that are created by the returned ErrorBuilder will carry the code.
+func (eb *ErrorBuilder) WithCode(code Code) *ErrorBuilder {
+	clone := *eb
+	clone.code = code
+	return &clone
+}
+
+// GetCode returns the code of the specified error.
+// The whole error tree is traversed, the code of outer errors takes precedence over
+// the code of their causes. If no error carries a code an empty Code is returned.
+func GetCode(err error) Code {
+	var code Code
+	walkErrors(err, func(err error) {
+		if code != "" {
+			return
+		}
+		if e, ok := err.(*Error); ok {
+			code = e.code
+		}
+	})
+	return code
+}
@@ -0,0 +1,132 @@
+package serrors_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+const (
+	codeNotFound serrors.Code = "not_found"
+	codeConflict serrors.Code = "conflict"
+)
+
+func TestGetCode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		error        error
+		expectedCode serrors.Code
+	}{
+		{
+			name:         "error is nil",
+			error:        nil,
+			expectedCode: "",
+		},
+		{
+			name:         "not an error",
+			error:        errors.New("some error"),
+			expectedCode: "",
+		},
+		{
+			name:         "no code",
+			error:        serrors.New("some error"),
+			expectedCode: "",
+		},
+		{
+			name:         "regular",
+			error:        serrors.New("some error").WithCode(codeNotFound),
+			expectedCode: codeNotFound,
+		},
+		{
+			name:         "wrapped",
+			error:        fmt.Errorf("wrapped: %w", serrors.New("some error").WithCode(codeNotFound)),
+			expectedCode: codeNotFound,
+		},
+		{
+			name: "outer code takes precedence",
+			error: serrors.Wrap(serrors.New("some error").WithCode(codeNotFound), "outer").
+				WithCode(codeConflict),
+			expectedCode: codeConflict,
+		},
+		{
+			name:         "builder",
+			error:        serrors.NewBuilder().WithCode(codeNotFound).With("k", "v").New("some error"),
+			expectedCode: codeNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			Equal(t, tc.expectedCode, serrors.GetCode(tc.error))
+		})
+	}
+}
+
+func TestCode_Is(t *testing.T) {
+	err := serrors.Wrap(
+		fmt.Errorf("wrapped: %w", serrors.New("some error").WithCode(codeNotFound)),
+		"outer",
+	).WithCode(codeConflict)
+
+	Equal(t, true, errors.Is(err, codeNotFound))
+	Equal(t, true, errors.Is(err, codeConflict))
+	Equal(t, false, errors.Is(err, serrors.Code("unknown")))
+	Equal(t, false, errors.Is(serrors.New("some error"), serrors.Code("")))
+	Equal(t, "not_found", codeNotFound.Error())
+}
+
+func TestCode_WithCodeDoesNotModify(t *testing.T) {
+	err1 := serrors.New("some error")
+	err2 := err1.WithCode(codeNotFound)
+	Equal(t, serrors.Code(""), serrors.GetCode(err1))
+	Equal(t, codeNotFound, serrors.GetCode(err2))
+	Equal(t, true, errors.Is(err2, err1))
+
+	base := serrors.NewBuilder().With("k", "v")
+	derived := base.WithCode(codeNotFound)
+	Equal(t, serrors.Code(""), serrors.GetCode(base.New("some error")))
+	Equal(t, codeNotFound, serrors.GetCode(derived.New("some error")))
+	Equal(t, map[string]any{"k": "v"}, serrors.GetFields(derived.New("some error")))
+}
+
+func TestCode_Stack(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	cause := serrors.New("error 1").WithCode(codeNotFound) // [TestCode_Stack01]
+	err := serrors.Wrap(cause, "error 2").With("k", "v")   // [TestCode_Stack00]
+
+	t.Run("GetStack", func(t *testing.T) {
+		expectedStack := []serrors.ErrorStack{
+			{
+				ErrorMessage: "error 2",
+				Fields:       map[string]any{"k": "v"},
+				StackTrace: []serrors.StackFrame{
+					buildStackFrameFromMarker(t, filename, "TestCode_Stack00"),
+				},
+			},
+			{
+				ErrorMessage: "error 1",
+				Code:         codeNotFound,
+				StackTrace: []serrors.StackFrame{
+					buildStackFrameFromMarker(t, filename, "TestCode_Stack01"),
+				},
+			},
+		}
+		skipWithoutStack(t)
+		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
+	})
+
+	t.Run("extra verbose", func(t *testing.T) {
+		skipWithoutStack(t)
+		expected := fmt.Sprintf("error 2\n[k=v]\n%s\nerror 1 (code=not_found)\n%s\n",
+			generateExpectedStack(t, filename, "TestCode_Stack00"),
+			generateExpectedStack(t, filename, "TestCode_Stack01"),
+		)
+		Equal(t, expected, fmt.Sprintf("%+v", err))
+	})
+}
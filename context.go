@@ -0,0 +1,68 @@
+package serrors
+
+import (
+	"context"
+)
+
+type contextFieldsKey struct{}
+
+// ContextWith returns a copy of ctx that carries the field key with value.
+// Errors that are created with NewCtx, ErrorfCtx, WrapCtx, WrapfCtx or with an ErrorBuilder
+// created by NewBuilderCtx will contain all fields of the context.
+// Fields that are added to the error itself (e.g. using With) take precedence over the fields of the context.
+func ContextWith(ctx context.Context, key string, value any) context.Context {
+	return context.WithValue(ctx, contextFieldsKey{}, withField(FieldsFromContext(ctx), key, value))
+}
+
+// FieldsFromContext returns the fields that were added to ctx using ContextWith.
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).([]Field)
+	return fields
+}
+
+// NewCtx creates a new Error with the supplied message.
+// The error will contain all fields of the context.
+func NewCtx(ctx context.Context, message string) *Error {
+	err := New(message)
+	err.fields = FieldsFromContext(ctx)
+	return err
+}
+
+// ErrorfCtx creates a new Error with the supplied message formatted according to a format specifier.
+// Errors that are referenced with the %w verb will be added as causes for this error.
+// The error will contain all fields of the context.
+func ErrorfCtx(ctx context.Context, format string, a ...any) *Error {
+	err := Errorf(format, a...)
+	err.fields = FieldsFromContext(ctx)
+	return err
+}
+
+// WrapCtx creates a new Error with the supplied message.
+// The passed in error will be added as a cause for this error.
+// The error will contain all fields of the context.
+func WrapCtx(ctx context.Context, err error, message string) *Error {
+	serr := Wrap(err, message)
+	serr.fields = FieldsFromContext(ctx)
+	return serr
+}
+
+// WrapfCtx creates a new Error with the supplied message formatted according to a format specifier.
+// The passed in error will be added as a cause for this error.
+// Errors that are referenced with the %w verb will be added as additional causes for this error.
+// The error will contain all fields of the context.
+func WrapfCtx(ctx context.Context, err error, format string, a ...any) *Error {
+	serr := Wrapf(err, format, a...)
+	serr.fields = FieldsFromContext(ctx)
+	return serr
+}
+
+// NewBuilderCtx creates a new ErrorBuilder that contains all fields of the context.
+// Fields that are added to the ErrorBuilder take precedence over the fields of the context.
+func NewBuilderCtx(ctx context.Context) *ErrorBuilder {
+	eb := NewBuilder()
+	eb.fields = FieldsFromContext(ctx)
+	return eb
+}
@@ -0,0 +1,84 @@
+package serrors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func TestContext(t *testing.T) {
+	ctx := serrors.ContextWith(context.Background(), "request_id", "abc")
+	ctx = serrors.ContextWith(ctx, "user_id", 1)
+
+	expectedFields := map[string]any{
+		"request_id": "abc",
+		"user_id":    1,
+		"key":        "value",
+	}
+
+	t.Run("FieldsFromContext", func(t *testing.T) {
+		Equal(t, []serrors.Field{
+			{Key: "request_id", Value: "abc"},
+			{Key: "user_id", Value: 1},
+		}, serrors.FieldsFromContext(ctx))
+		Nil(t, serrors.FieldsFromContext(context.Background()))
+	})
+
+	t.Run("ContextWith does not modify the parent context", func(t *testing.T) {
+		child := serrors.ContextWith(ctx, "user_id", 2)
+		Equal(t, []serrors.Field{
+			{Key: "request_id", Value: "abc"},
+			{Key: "user_id", Value: 1},
+		}, serrors.FieldsFromContext(ctx))
+		Equal(t, []serrors.Field{
+			{Key: "request_id", Value: "abc"},
+			{Key: "user_id", Value: 2},
+		}, serrors.FieldsFromContext(child))
+	})
+
+	t.Run("NewCtx", func(t *testing.T) {
+		err := serrors.NewCtx(ctx, "some error").With("key", "value")
+		Equal(t, "some error", err.Error())
+		Equal(t, expectedFields, serrors.GetFields(err))
+	})
+
+	t.Run("ErrorfCtx", func(t *testing.T) {
+		err := serrors.ErrorfCtx(ctx, "some error %d", 1).With("key", "value")
+		Equal(t, "some error 1", err.Error())
+		Equal(t, expectedFields, serrors.GetFields(err))
+	})
+
+	t.Run("WrapCtx", func(t *testing.T) {
+		err := serrors.WrapCtx(ctx, errors.New("cause"), "some error").With("key", "value")
+		Equal(t, "some error: cause", err.Error())
+		Equal(t, expectedFields, serrors.GetFields(err))
+	})
+
+	t.Run("WrapfCtx", func(t *testing.T) {
+		err := serrors.WrapfCtx(ctx, errors.New("cause"), "some error %d", 1).With("key", "value")
+		Equal(t, "some error 1: cause", err.Error())
+		Equal(t, expectedFields, serrors.GetFields(err))
+	})
+
+	t.Run("NewBuilderCtx", func(t *testing.T) {
+		err := serrors.NewBuilderCtx(ctx).With("key", "value").New("some error")
+		Equal(t, "some error", err.Error())
+		Equal(t, expectedFields, serrors.GetFields(err))
+	})
+
+	t.Run("error fields take precedence", func(t *testing.T) {
+		err := serrors.NewCtx(ctx, "some error").With("user_id", 2)
+		Equal(t, map[string]any{"request_id": "abc", "user_id": 2}, serrors.GetFields(err))
+
+		err = serrors.NewBuilderCtx(ctx).With("user_id", 3).New("some error")
+		Equal(t, map[string]any{"request_id": "abc", "user_id": 3}, serrors.GetFields(err))
+	})
+
+	t.Run("outer errors take precedence", func(t *testing.T) {
+		inner := serrors.NewCtx(serrors.ContextWith(ctx, "user_id", 2), "inner")
+		err := serrors.WrapCtx(ctx, inner, "outer")
+		Equal(t, map[string]any{"request_id": "abc", "user_id": 1}, serrors.GetFields(err))
+	})
+}
@@ -3,108 +3,252 @@ package serrors
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Error is the error that will be returned by ErrorBuilder and the functions
 // New, Errorf, Wrap and Wrapf.
 // It implements the stdlib error interface.
+// An Error is never modified after it was created, so it can be safely shared between goroutines.
 type Error struct {
 	message string
 	cause   error
-	fields  map[string]any
-	stack   []uintptr
+	// causes holds all causes when the error has multiple causes, cause is the first of them.
+	causes []error
+	fields []Field
+	code   Code
+	stack  *stack
+	// frames is set instead of stack when the stack frames are already resolved,
+	// e.g. for errors that were decoded from json.
+	frames []StackFrame
+	// origin is the error this error was derived from using With.
+	origin *Error
+	// sentinel is the sentinel this error was created from.
+	sentinel *SentinelError
+	// fullMessage is set when the message already contains the text of the cause,
+	// e.g. when the cause was referenced by a %w verb in the middle of the message.
+	fullMessage bool
 }
 
 // Unwrap provides compatibility for Go 1.13 error chains.
+// For errors with multiple causes (see Errorf and Wrapf) only the first cause is returned,
+// errors.Is and errors.As still consider all causes.
 func (e *Error) Unwrap() error { return e.cause }
 
 // Cause returns the cause of this error.
+// For errors with multiple causes (see Errorf and Wrapf) the first cause is returned.
 func (e *Error) Cause() error { return e.cause }
 
-// With adds the field key with the value to the error fields.
+// With returns a copy of the error with the field key set to value.
+// The error itself is not modified, however errors.Is will report the copy
+// and the error as equal.
 func (e *Error) With(key string, value any) *Error {
-	if e.fields == nil {
-		e.fields = make(map[string]any)
+	clone := *e
+	clone.fields = withField(e.fields, key, value)
+	clone.origin = e.root()
+	return &clone
+}
+
+// Is reports whether the target is the same error as this error,
+// this includes copies that were created with With.
+// If the target is a Code, Is reports whether this error carries the code.
+// If the target is a SentinelError, Is reports whether this error was created from the sentinel.
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case *Error:
+		if t != nil && e.root() == t.root() {
+			return true
+		}
+	case Code:
+		if e.code != "" && e.code == t {
+			return true
+		}
+	case *SentinelError:
+		if t != nil && e.sentinel == t {
+			return true
+		}
+	}
+	// the first cause is reached by errors.Is using Unwrap, the additional causes need to be checked here.
+	for _, cause := range e.additionalCauses() {
+		if errors.Is(cause, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first cause that matches target for errors with multiple causes,
+// so errors.As considers the causes in order.
+func (e *Error) As(target any) bool {
+	for _, cause := range e.causes {
+		if errors.As(cause, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// additionalCauses returns all causes except the first one.
+func (e *Error) additionalCauses() []error {
+	if len(e.causes) == 0 {
+		return nil
+	}
+	return e.causes[1:]
+}
+
+// root returns the error this error was derived from.
+func (e *Error) root() *Error {
+	if e.origin != nil {
+		return e.origin
 	}
-	e.fields[key] = value
 	return e
 }
 
 // New creates a new Error with the supplied message.
 func New(message string) *Error {
-	return &Error{
-		message: message,
-		cause:   nil,
-		fields:  nil,
-		stack:   collectStack(),
-	}
+	return newError(currentStackOptions(), message)
 }
 
 // Errorf creates a new Error with the supplied message formatted according to a format specifier.
+// Errors that are referenced with the %w verb will be added as causes for this error,
+// multiple %w verbs result in an error with multiple causes.
 func Errorf(format string, a ...any) *Error {
-	return New(fmt.Sprintf(format, a...))
+	return errorf(currentStackOptions(), format, a)
 }
 
 // Wrap creates a new Error with the supplied message.
 // The passed in error will be added as a cause for this error.
 func Wrap(err error, message string) *Error {
-	return &Error{
-		message: message,
-		cause:   err,
-		fields:  nil,
-		stack:   collectStack(),
-	}
+	return wrap(currentStackOptions(), err, message)
 }
 
 // Wrapf creates a new Error with the supplied message formatted according to a format specifier.
 // The passed in error will be added as a cause for this error.
+// Errors that are referenced with the %w verb will be added as additional causes for this error.
 func Wrapf(err error, format string, a ...any) *Error {
-	return Wrap(err, fmt.Sprintf(format, a...))
+	return wrapf(currentStackOptions(), err, format, a)
 }
 
-// GetFields will return all fields that are added to the specified error.
-func GetFields(err error) map[string]any {
-	if err == nil {
-		return nil
+func newError(opts stackOptions, message string) *Error {
+	opts, ok := opts.forMode()
+	if !ok {
+		return &Error{
+			message: message,
+			cause:   nil,
+			fields:  nil,
+		}
+	}
+	var buf [stackBufferSize]uintptr
+	err := newErrorWithStack(collectStack(opts, buf[:]), opts)
+	err.message = message
+	return err
+}
+
+// newErrorWithStack returns a new Error with the stack of the program counters,
+// the stack is resolved right away when the frame filters are applied at capture.
+func newErrorWithStack(pcs []uintptr, opts stackOptions) *Error {
+	err := allocError(pcs, opts)
+	if currentFrameFilterMode() == FilterAtCapture && err.stack != nil {
+		err.stack.resolveNow()
 	}
+	return err
+}
 
-	// errors are nested, and we don't want nested errors to
-	// overwrite fields of the errors above
-	// We need to collect the fields first and then
-	// reverse iterate them and add the key-values to the final map
-	var collectedFields []map[string]any
-	for err != nil {
-		if e, ok := err.(*Error); ok {
-			if len(e.fields) > 0 {
-				collectedFields = append(collectedFields, e.fields)
-			}
+func errorf(opts stackOptions, format string, a []any) *Error {
+	message, causes := formatMessage(format, a)
+	err := newError(opts, message)
+	switch len(causes) {
+	case 0:
+	case 1:
+		err.cause = causes[0]
+		// remove the text of the cause from the message, Error() will add it again.
+		if s, found := strings.CutSuffix(message, ": "+causes[0].Error()); found {
+			err.message = s
+		} else {
+			err.fullMessage = true
 		}
-		err = errors.Unwrap(err)
+	default:
+		err.cause = causes[0]
+		err.causes = causes
+		err.fullMessage = true
 	}
+	return err
+}
 
-	if len(collectedFields) == 0 {
-		return nil
+func wrap(opts stackOptions, err error, message string) *Error {
+	serr := newError(opts, message)
+	serr.cause = err
+	return serr
+}
+
+func wrapf(opts stackOptions, err error, format string, a []any) *Error {
+	if err == nil {
+		return errorf(opts, format, a)
+	}
+	message, causes := formatMessage(format, a)
+	serr := wrap(opts, err, message)
+	if len(causes) > 0 {
+		serr.causes = append([]error{err}, causes...)
 	}
+	return serr
+}
 
-	fields := make(map[string]any)
-	for i := len(collectedFields) - 1; i >= 0; i-- {
-		for k, v := range collectedFields[i] {
-			fields[k] = v
+// formatMessage formats according to a format specifier and returns the resulting message.
+// Errors that are referenced with the %w verb are returned as causes.
+func formatMessage(format string, a []any) (string, []error) {
+	if !strings.Contains(format, "%w") {
+		return fmt.Sprintf(format, a...), nil
+	}
+
+	// Error and MultiError implement fmt.Formatter and would add their fields to the message,
+	// make sure only the error text will be used.
+	args := make([]any, len(a))
+	for i, arg := range a {
+		switch e := arg.(type) {
+		case *Error:
+			args[i] = plainError{e}
+		case *MultiError:
+			args[i] = plainError{e}
+		default:
+			args[i] = arg
+		}
+	}
+
+	wrapped := fmt.Errorf(format, args...)
+	causes := unwrapErrors(wrapped)
+	for i, cause := range causes {
+		if p, ok := cause.(plainError); ok {
+			causes[i] = p.error
 		}
 	}
-	return fields
+	return wrapped.Error(), causes
+}
+
+// plainError hides all methods of an error except Error().
+type plainError struct {
+	error
+}
+
+// GetFields will return all fields that are added to the specified error.
+// The whole error tree is traversed, this includes errors that have multiple causes (e.g. errors.Join).
+// Fields of outer errors take precedence over fields of their causes, and fields of earlier causes
+// take precedence over fields of later causes.
+// Use GetOrderedFields to get the fields in a deterministic order.
+func GetFields(err error) map[string]any {
+	return fieldsToMap(GetOrderedFields(err))
 }
 
 // GetFieldsAsCombinedSlice will return all fields as a slice that are added to the specified error.
 // The format will be [key1, value1, key2, value2, ..., keyN, valueN].
+// The order of the fields is the same as in GetOrderedFields.
 func GetFieldsAsCombinedSlice(err error) []any {
-	fields := GetFields(err)
+	fields := GetOrderedFields(err)
 	if fields == nil {
 		return nil
 	}
-	args := make([]any, 0, len(fields))
-	for s, a := range fields {
-		args = append(args, s, a)
+	args := make([]any, 0, len(fields)*2) //nolint:gomnd // every field consists of a key and a value
+	for _, field := range fields {
+		args = append(args, field.Key, field.Value)
 	}
 	return args
 }
@@ -62,11 +62,7 @@ func addUserToRole(userName, roleName string) error {
 
 func ExampleError() {
 	if err := addUserToRole("joe", "guest"); err != nil {
-		slog.Error("name validation failed",
-			"error", err.Error(),
-			slog.Group("details", serrors.GetFieldsAsCombinedSlice(err)...),
-			"stack", serrors.GetStack(err),
-		)
+		slog.Error("name validation failed", "error", err)
 		return
 	}
 }
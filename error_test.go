@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/Eun/serrors"
@@ -43,6 +44,7 @@ func TestError(t *testing.T) {
 			},
 		}
 		Equal(t, expectedFields, serrors.GetFields(err))
+		skipWithoutStack(t)
 		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
 	})
 
@@ -86,10 +88,115 @@ func TestError(t *testing.T) {
 			},
 		}
 		Equal(t, expectedFields, serrors.GetFields(err))
+		skipWithoutStack(t)
 		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
 	})
 }
 
+func TestErrorf_WrapVerb(t *testing.T) {
+	errBase := errors.New("base error")
+	errOther := errors.New("other error")
+
+	messages := func(stack []serrors.ErrorStack) []string {
+		result := make([]string, len(stack))
+		for i := range stack {
+			result[i] = stack[i].ErrorMessage
+		}
+		return result
+	}
+
+	t.Run("single %w at the end", func(t *testing.T) {
+		cause := serrors.New("deep error").With("k", "v")
+		err := serrors.Errorf("load %s: %w", "file", cause)
+		Equal(t, "load file: deep error", err.Error())
+		Equal(t, true, errors.Is(err, cause))
+		Equal(t, cause, errors.Unwrap(err))
+		Equal(t, map[string]any{"k": "v"}, serrors.GetFields(err))
+		Equal(t, []string{"load file", "deep error"}, messages(serrors.GetStack(err)))
+	})
+
+	t.Run("single %w in the middle", func(t *testing.T) {
+		err := serrors.Errorf("unable to use (%w) here", errBase)
+		Equal(t, "unable to use (base error) here", err.Error())
+		Equal(t, true, errors.Is(err, errBase))
+		Equal(t, []string{"unable to use () here", "base error"}, messages(serrors.GetStack(err)))
+	})
+
+	t.Run("single %w only", func(t *testing.T) {
+		cause := serrors.New("deep error")
+		err := serrors.Errorf("%w", cause)
+		Equal(t, "deep error", err.Error())
+		Equal(t, cause, errors.Unwrap(err))
+		Equal(t, []string{"", "deep error"}, messages(serrors.GetStack(err)))
+		Equal(t, 1, strings.Count(fmt.Sprintf("%+v", err), "deep error"))
+	})
+
+	t.Run("multiple %w", func(t *testing.T) {
+		err := serrors.Errorf("%w, %w", errBase, serrors.New("other").With("k", "v"))
+		Equal(t, "base error, other", err.Error())
+		Equal(t, true, errors.Is(err, errBase))
+		Equal(t, errBase, errors.Unwrap(err))
+		Equal(t, errBase, err.Cause())
+		Equal(t, map[string]any{"k": "v"}, serrors.GetFields(err))
+
+		stack := serrors.GetStack(err)
+		Equal(t, 1, len(stack))
+		Equal(t, "", stack[0].ErrorMessage)
+		Equal(t, 2, len(stack[0].Causes))
+		Equal(t, []string{"base error"}, messages(stack[0].Causes[0]))
+		Equal(t, []string{"other"}, messages(stack[0].Causes[1]))
+	})
+
+	t.Run("multiple %w with text", func(t *testing.T) {
+		err := serrors.Errorf("a %w b %w", errBase, errString("other"))
+		Equal(t, "a base error b other", err.Error())
+		Equal(t, true, errors.Is(err, errString("other")))
+
+		var target errString
+		Equal(t, true, errors.As(err, &target))
+		Equal(t, errString("other"), target)
+
+		stack := serrors.GetStack(err)
+		Equal(t, 1, len(stack))
+		Equal(t, "a b", stack[0].ErrorMessage)
+		Equal(t, []string{"base error"}, messages(stack[0].Causes[0]))
+		Equal(t, []string{"other"}, messages(stack[0].Causes[1]))
+	})
+
+	t.Run("no %w", func(t *testing.T) {
+		err := serrors.Errorf("some error %v", errBase)
+		Equal(t, "some error base error", err.Error())
+		Nil(t, errors.Unwrap(err))
+	})
+
+	t.Run("Wrapf with %w", func(t *testing.T) {
+		err := serrors.Wrapf(errBase, "failed because of %w", errOther)
+		Equal(t, "failed because of other error: base error", err.Error())
+		Equal(t, true, errors.Is(err, errBase))
+		Equal(t, true, errors.Is(err, errOther))
+		Equal(t, errBase, errors.Unwrap(err))
+		Equal(t, errBase, err.Cause())
+
+		stack := serrors.GetStack(err)
+		Equal(t, 1, len(stack))
+		Equal(t, "failed because of", stack[0].ErrorMessage)
+		Equal(t, 2, len(stack[0].Causes))
+	})
+
+	t.Run("Wrapf nil error with %w", func(t *testing.T) {
+		err := serrors.Wrapf(nil, "failed: %w", errOther)
+		Equal(t, "failed: other error", err.Error())
+		Equal(t, true, errors.Is(err, errOther))
+	})
+
+	t.Run("builder", func(t *testing.T) {
+		err := serrors.NewBuilder().With("k", "v").Errorf("failed: %w", errBase)
+		Equal(t, "failed: base error", err.Error())
+		Equal(t, true, errors.Is(err, errBase))
+		Equal(t, map[string]any{"k": "v"}, serrors.GetFields(err))
+	})
+}
+
 func TestGetFields(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -121,6 +228,22 @@ func TestGetFields(t *testing.T) {
 			error:          fmt.Errorf("some error: %w", serrors.New("some error").With("k", "v")),
 			expectedFields: map[string]any{"k": "v"},
 		},
+		{
+			name: "joined",
+			error: serrors.Wrap(errors.Join(
+				serrors.New("error 1").With("k", "v1").With("k1", "v1"),
+				serrors.New("error 2").With("k", "v2").With("k2", "v2"),
+			), "some error").With("k3", "v3"),
+			expectedFields: map[string]any{"k": "v1", "k1": "v1", "k2": "v2", "k3": "v3"},
+		},
+		{
+			name: "multiple %w",
+			error: fmt.Errorf("some error: %w, %w",
+				serrors.New("error 1").With("k1", "v1"),
+				serrors.Wrap(serrors.New("error 3").With("k", "v3"), "error 2").With("k", "v2"),
+			),
+			expectedFields: map[string]any{"k": "v2", "k1": "v1"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -172,6 +295,29 @@ func TestGetFieldsAsArguments(t *testing.T) {
 	}
 }
 
+func TestError_With(t *testing.T) {
+	t.Run("does not modify the error", func(t *testing.T) {
+		err1 := serrors.New("some error").With("key1", "value1")
+		err2 := err1.With("key2", "value2")
+		err3 := err1.With("key1", "overwritten")
+
+		Equal(t, map[string]any{"key1": "value1"}, serrors.GetFields(err1))
+		Equal(t, map[string]any{"key1": "value1", "key2": "value2"}, serrors.GetFields(err2))
+		Equal(t, map[string]any{"key1": "overwritten"}, serrors.GetFields(err3))
+	})
+	t.Run("copies are equal to the original", func(t *testing.T) {
+		err1 := serrors.New("some error")
+		err2 := err1.With("key1", "value1")
+		err3 := err2.With("key2", "value2")
+
+		Equal(t, true, errors.Is(err2, err1))
+		Equal(t, true, errors.Is(err3, err1))
+		Equal(t, true, errors.Is(err1, err3))
+		Equal(t, true, errors.Is(fmt.Errorf("wrapped: %w", err3), err1))
+		Equal(t, false, errors.Is(err3, serrors.New("some error")))
+	})
+}
+
 func TestUnwrap(t *testing.T) {
 	t.Run("wrapped error", func(t *testing.T) {
 		err1 := errors.New("error1")
@@ -220,3 +366,67 @@ func TestAs(t *testing.T) {
 		Nil(t, cause2)
 	})
 }
+
+func TestError_Allocations(t *testing.T) {
+	cause := errors.New("cause")
+	testCases := []struct {
+		name     string
+		fn       func()
+		expected float64
+	}{
+		{
+			name: "New",
+			fn: func() {
+				benchmarkError = serrors.New("some error")
+			},
+			expected: 1,
+		},
+		{
+			name: "Wrap",
+			fn: func() {
+				benchmarkError = serrors.Wrap(cause, "some error")
+			},
+			expected: 1,
+		},
+		{
+			name: "New with deep stack",
+			fn: func() {
+				benchmarkError = recursiveError(serrors.DefaultStackDepth)
+			},
+			expected: 1,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			Equal(t, tc.expected, testing.AllocsPerRun(100, tc.fn))
+		})
+	}
+}
+
+// benchmarkError prevents the compiler from optimizing the benchmarks away.
+var benchmarkError error
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkError = serrors.New("some error")
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	cause := errors.New("cause")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkError = serrors.Wrap(cause, "some error")
+	}
+}
+
+func BenchmarkWith(b *testing.B) {
+	err := serrors.New("some error")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkError = err.With("key", "value")
+	}
+}
@@ -5,36 +5,42 @@ package serrors
 
 import (
 	"runtime"
-	"strings"
 )
 
-func collectStack() []uintptr {
-	const depth = 64
-	var pcs [depth]uintptr
-	n := runtime.Callers(0, pcs[:])
-	return pcs[0:n]
+// collectStack captures the program counters of the caller into buf,
+// if buf is too small a new buffer will be allocated.
+func collectStack(opts stackOptions, buf []uintptr) []uintptr {
+	if size := opts.depth + opts.skip + internalFrames; size <= len(buf) {
+		buf = buf[:size]
+	} else {
+		buf = make([]uintptr, size)
+	}
+	// skip runtime.Callers and collectStack
+	n := runtime.Callers(2, buf) //nolint:gomnd // see comment above
+	return buf[0:n]
 }
 
-func resolveStackForStackFrames(stackFrames []uintptr) []StackFrame {
+func resolveStackForStackFrames(stackFrames []uintptr, opts stackOptions) []StackFrame {
 	var result []StackFrame
-	frames := runtime.CallersFrames(stackFrames)
-	for {
-		frame, more := frames.Next()
-		if !more {
-			break
+	filters := currentFrameFilters()
+	skip := opts.skip
+	skipHelpers := true
+	stackCache.forEachFrame(stackFrames, func(frame StackFrame) bool {
+		if frame.Func == "" || isInternalFunc(frame.Func) {
+			return true
 		}
-
-		if strings.HasPrefix(frame.Function, "runtime.") ||
-			strings.HasPrefix(frame.Function, "testing.") ||
-			strings.HasPrefix(frame.Function, "github.com/Eun/serrors.") {
-			continue
+		decision := filterFrame(filters, frame)
+		if decision != DropFrame {
+			switch {
+			case skip > 0:
+				skip--
+			case skipHelpers && isHelper(frame.Func):
+			default:
+				skipHelpers = false
+				result = append(result, frame)
+			}
 		}
-
-		result = append(result, StackFrame{
-			File: frame.File,
-			Func: frame.Function,
-			Line: frame.Line,
-		})
-	}
+		return decision != KeepFrameAndStop && (opts.depth <= 0 || len(result) < opts.depth)
+	})
 	return result
 }
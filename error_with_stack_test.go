@@ -0,0 +1,7 @@
+//go:build !serrors_without_stack
+// +build !serrors_without_stack
+
+package serrors_test
+
+// stackEnabled reports whether stack traces are captured, see error_without_stack_test.go.
+const stackEnabled = true
@@ -3,10 +3,10 @@
 
 package serrors
 
-func (e *Error) collectStack() []uintptr {
+func collectStack(stackOptions, []uintptr) []uintptr {
 	return nil
 }
 
-func resolveStackForStackFrames([]uintptr) []StackFrame {
+func resolveStackForStackFrames([]uintptr, stackOptions) []StackFrame {
 	return nil
 }
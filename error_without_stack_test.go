@@ -0,0 +1,28 @@
+//go:build serrors_without_stack
+// +build serrors_without_stack
+
+package serrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+// Run with go test -tags serrors_without_stack ./...
+
+// stackEnabled reports whether stack traces are captured, see error_with_stack_test.go.
+const stackEnabled = false
+
+func TestWithoutStack(t *testing.T) {
+	err := serrors.Wrap(serrors.Wrap(errors.New("error 3"), "error 2"), "error 1").With("k", "v")
+
+	CompareErrorStack(t, []serrors.ErrorStack{
+		{ErrorMessage: "error 1", Fields: map[string]any{"k": "v"}},
+		{ErrorMessage: "error 2"},
+		{ErrorMessage: "error 3"},
+	}, serrors.GetStack(err))
+	Equal(t, "error 1\n[k=v]\nerror 2\nerror 3\n", fmt.Sprintf("%+v", err))
+}
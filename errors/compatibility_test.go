@@ -0,0 +1,112 @@
+//go:build !serrors_without_stack
+// +build !serrors_without_stack
+
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/Eun/serrors"
+	"github.com/Eun/serrors/errors"
+)
+
+// containsLines reports whether every line of s is a line of other.
+func containsLines(s, other string) bool {
+	lines := make(map[string]struct{})
+	for _, line := range strings.Split(other, "\n") {
+		lines[line] = struct{}{}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		if _, ok := lines[line]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCompatibility(t *testing.T) {
+	testCases := []struct {
+		name      string
+		create    func() (error, error)
+		withStack bool
+	}{
+		{
+			name: "New",
+			create: func() (error, error) {
+				return errors.New("some error"), pkgerrors.New("some error")
+			},
+			withStack: true,
+		},
+		{
+			name: "Errorf",
+			create: func() (error, error) {
+				return errors.Errorf("some error %d", 1), pkgerrors.Errorf("some error %d", 1)
+			},
+			withStack: true,
+		},
+		{
+			name: "WithStack",
+			create: func() (error, error) {
+				return errors.WithStack(io.EOF), pkgerrors.WithStack(io.EOF)
+			},
+			withStack: true,
+		},
+		{
+			name: "Wrap",
+			create: func() (error, error) {
+				return errors.Wrap(io.EOF, "some error"), pkgerrors.Wrap(io.EOF, "some error")
+			},
+			withStack: true,
+		},
+		{
+			name: "Wrapf",
+			create: func() (error, error) {
+				return errors.Wrapf(io.EOF, "some error %d", 1), pkgerrors.Wrapf(io.EOF, "some error %d", 1)
+			},
+			withStack: true,
+		},
+		{
+			name: "WithMessage",
+			create: func() (error, error) {
+				return errors.WithMessage(io.EOF, "some error"), pkgerrors.WithMessage(io.EOF, "some error")
+			},
+			withStack: false,
+		},
+		{
+			name: "WithMessagef",
+			create: func() (error, error) {
+				return errors.WithMessagef(io.EOF, "some error %d", 1), pkgerrors.WithMessagef(io.EOF, "some error %d", 1)
+			},
+			withStack: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err, pkgErr := tc.create()
+			Equal(t, pkgErr.Error(), err.Error())
+			Equal(t, fmt.Sprintf("%v", pkgErr), fmt.Sprintf("%v", err))
+			Equal(t, pkgerrors.Cause(pkgErr).Error(), errors.Cause(err).Error())
+
+			verbose := fmt.Sprintf("%+v", err)
+			pkgVerbose := fmt.Sprintf("%+v", pkgErr)
+			if !containsLines(verbose, pkgVerbose) {
+				t.Fatalf("expected all lines of\n%s\nto be in\n%s", verbose, pkgVerbose)
+			}
+
+			var serr *serrors.Error
+			Equal(t, true, errors.As(err, &serr))
+			Equal(t, tc.withStack, len(serrors.GetStack(err)[0].StackTrace) > 0)
+			if tc.withStack {
+				Equal(t, fmt.Sprintf("%+v", pkgErr.(interface{ StackTrace() errors.StackTrace }).StackTrace()[0]),
+					fmt.Sprintf("%+v", serr.StackTrace()[0]))
+			}
+		})
+	}
+}
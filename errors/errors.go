@@ -0,0 +1,117 @@
+// Package errors provides the API of github.com/pkg/errors implemented on top of serrors.Error.
+//
+// It can be used to migrate from github.com/pkg/errors by changing the import path first,
+// all functions return errors that are created by serrors, so fields can be added incrementally
+// by switching to the functions of serrors afterwards.
+package errors
+
+import (
+	"errors"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/Eun/serrors"
+)
+
+// StackTrace is the stack trace that is returned by serrors.Error.StackTrace.
+type StackTrace = pkgerrors.StackTrace
+
+// Frame is a single frame of a StackTrace.
+type Frame = pkgerrors.Frame
+
+var (
+	// stackBuilder skips the frames of this package.
+	stackBuilder = serrors.NewBuilder().WithStackSkip(1)
+	// messageBuilder creates errors without a stack, like WithMessage of github.com/pkg/errors.
+	messageBuilder = serrors.NewBuilder().WithoutStack()
+)
+
+// New returns an error with the supplied message and the stack of the caller.
+func New(message string) error {
+	return stackBuilder.New(message)
+}
+
+// Errorf formats according to a format specifier and returns the string as an error
+// with the stack of the caller.
+// Unlike github.com/pkg/errors, errors referenced with the %w verb are added as causes.
+func Errorf(format string, args ...any) error {
+	return stackBuilder.Errorf(format, args...)
+}
+
+// WithStack annotates err with the stack of the caller.
+// If err is nil, WithStack returns nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return stackBuilder.Wrap(err, "")
+}
+
+// Wrap returns an error annotating err with the stack of the caller and the supplied message.
+// If err is nil, Wrap returns nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return stackBuilder.Wrap(err, message)
+}
+
+// Wrapf returns an error annotating err with the stack of the caller and the format specifier.
+// If err is nil, Wrapf returns nil.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return stackBuilder.Wrapf(err, format, args...)
+}
+
+// WithMessage annotates err with a new message.
+// If err is nil, WithMessage returns nil.
+func WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return messageBuilder.Wrap(err, message)
+}
+
+// WithMessagef annotates err with the format specifier.
+// If err is nil, WithMessagef returns nil.
+func WithMessagef(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return messageBuilder.Wrapf(err, format, args...)
+}
+
+// Cause returns the underlying cause of the error, if possible.
+// The chain of errors implementing Cause() error is followed until an error is reached that has no cause,
+// for errors with multiple causes the first cause is followed.
+func Cause(err error) error {
+	for err != nil {
+		causer, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		cause := causer.Cause()
+		if cause == nil {
+			break
+		}
+		err = cause
+	}
+	return err
+}
+
+// Is reports whether any error in err's chain matches target, see errors.Is.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target, see errors.As.
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err, see errors.Unwrap.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
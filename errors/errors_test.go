@@ -0,0 +1,33 @@
+package errors_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/Eun/serrors"
+	"github.com/Eun/serrors/errors"
+)
+
+func TestNil(t *testing.T) {
+	Nil(t, errors.WithStack(nil))
+	Nil(t, errors.Wrap(nil, "some error"))
+	Nil(t, errors.Wrapf(nil, "some error %d", 1))
+	Nil(t, errors.WithMessage(nil, "some error"))
+	Nil(t, errors.WithMessagef(nil, "some error %d", 1))
+	Nil(t, errors.Cause(nil))
+}
+
+func TestCause(t *testing.T) {
+	err := errors.New("some error")
+	Equal(t, err, errors.Cause(err))
+	Equal(t, err, errors.Cause(errors.WithMessage(errors.Wrap(err, "wrapped"), "message")))
+	Equal(t, true, errors.Is(errors.Wrap(io.EOF, "wrapped"), io.EOF))
+	Equal(t, io.EOF, errors.Unwrap(errors.Wrap(io.EOF, "wrapped")))
+	Equal(t, io.EOF, errors.Cause(errors.Wrapf(io.EOF, "failed because of %w", io.ErrUnexpectedEOF)))
+}
+
+func TestFields(t *testing.T) {
+	err := serrors.Wrap(errors.Wrap(io.EOF, "some error"), "read failed").With("file", "config.yaml")
+	Equal(t, "read failed: some error: EOF", err.Error())
+	Equal(t, map[string]any{"file": "config.yaml"}, serrors.GetFields(err))
+}
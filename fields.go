@@ -0,0 +1,74 @@
+package serrors
+
+// Field is a key-value pair that is attached to an error.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// GetOrderedFields will return all fields that are added to the specified error in a deterministic order.
+// The fields of each error are returned in the order they were added, fields of outer errors come first.
+// Like in GetFields, each key is only returned once and fields of outer errors take precedence over
+// fields of their causes.
+func GetOrderedFields(err error) []Field {
+	if err == nil {
+		return nil
+	}
+
+	var result []Field
+	var seen map[string]struct{}
+	walkErrors(err, func(err error) {
+		for _, field := range ownFields(err) {
+			if _, ok := seen[field.Key]; ok {
+				continue
+			}
+			if seen == nil {
+				seen = make(map[string]struct{})
+			}
+			seen[field.Key] = struct{}{}
+			result = append(result, field)
+		}
+	})
+	return result
+}
+
+// ownFields returns the fields of the error itself, without the fields of its causes.
+func ownFields(err error) []Field {
+	switch e := err.(type) {
+	case *Error:
+		return e.fields
+	case *MultiError:
+		if e == nil {
+			return nil
+		}
+		return e.fields
+	}
+	return extractFields(err)
+}
+
+// withField returns a copy of fields with the value of the field with the specified key set.
+// If the key is already present the value will be replaced, and the field keeps its position.
+// The passed in fields are never modified, so they can be safely shared.
+func withField(fields []Field, key string, value any) []Field {
+	result := make([]Field, len(fields), len(fields)+1)
+	copy(result, fields)
+	for i := range result {
+		if result[i].Key == key {
+			result[i].Value = value
+			return result
+		}
+	}
+	return append(result, Field{Key: key, Value: value})
+}
+
+// fieldsToMap converts the fields into a map, it returns nil if there are no fields.
+func fieldsToMap(fields []Field) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+	result := make(map[string]any, len(fields))
+	for _, field := range fields {
+		result[field.Key] = field.Value
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package serrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func TestGetOrderedFields(t *testing.T) {
+	testCases := []struct {
+		name           string
+		error          error
+		expectedFields []serrors.Field
+	}{
+		{
+			name:           "error is nil",
+			error:          nil,
+			expectedFields: nil,
+		},
+		{
+			name:           "not an error",
+			error:          errors.New("some error"),
+			expectedFields: nil,
+		},
+		{
+			name:           "not containing fields",
+			error:          serrors.New("some error"),
+			expectedFields: nil,
+		},
+		{
+			name:  "insertion order",
+			error: serrors.New("some error").With("c", 1).With("a", 2).With("b", 3),
+			expectedFields: []serrors.Field{
+				{Key: "c", Value: 1},
+				{Key: "a", Value: 2},
+				{Key: "b", Value: 3},
+			},
+		},
+		{
+			name:  "overwritten key keeps its position",
+			error: serrors.New("some error").With("c", 1).With("a", 2).With("c", 3),
+			expectedFields: []serrors.Field{
+				{Key: "c", Value: 3},
+				{Key: "a", Value: 2},
+			},
+		},
+		{
+			name: "outer fields come first and take precedence",
+			error: serrors.Wrap(
+				fmt.Errorf("wrapped: %w", serrors.New("error 1").With("c", 1).With("a", 2)),
+				"error 2",
+			).With("b", 3).With("a", 4),
+			expectedFields: []serrors.Field{
+				{Key: "b", Value: 3},
+				{Key: "a", Value: 4},
+				{Key: "c", Value: 1},
+			},
+		},
+		{
+			name: "joined",
+			error: errors.Join(
+				serrors.New("error 1").With("b", 1).With("a", 2),
+				serrors.New("error 2").With("c", 3).With("a", 4),
+			),
+			expectedFields: []serrors.Field{
+				{Key: "b", Value: 1},
+				{Key: "a", Value: 2},
+				{Key: "c", Value: 3},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			Equal(t, tc.expectedFields, serrors.GetOrderedFields(tc.error))
+		})
+	}
+}
+
+func TestGetFieldsAsCombinedSlice_IsDeterministic(t *testing.T) {
+	err := serrors.Wrap(
+		serrors.New("error 1").With("k5", 5).With("k3", 3).With("k1", 1),
+		"error 2",
+	).With("k4", 4).With("k2", 2)
+
+	expected := []any{"k4", 4, "k2", 2, "k5", 5, "k3", 3, "k1", 1}
+	for i := 0; i < 100; i++ {
+		Equal(t, expected, serrors.GetFieldsAsCombinedSlice(err))
+	}
+}
+
+func BenchmarkGetFields(b *testing.B) {
+	err := serrors.Wrap(serrors.New("error 1").With("k1", "v1"), "error 2").With("k2", "v2")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = serrors.GetFields(err)
+	}
+}
@@ -1,12 +1,35 @@
 package serrors
 
 import (
+	"bytes"
 	"fmt"
 	"io"
-	"sort"
 	"strings"
+	"sync/atomic"
 )
 
+// FormatOptions configures the output of an Error when it is formatted with %+v.
+// The zero value elides the frames that each error has in common with its cause.
+type FormatOptions struct {
+	// FullStack prints the full stack trace of every error, including the frames
+	// it has in common with its cause.
+	FullStack bool
+}
+
+var formatOptions atomic.Pointer[FormatOptions]
+
+// SetFormatOptions sets the options that are used when an Error is formatted with %+v.
+func SetFormatOptions(opts FormatOptions) {
+	formatOptions.Store(&opts)
+}
+
+func currentFormatOptions() FormatOptions {
+	if opts := formatOptions.Load(); opts != nil {
+		return *opts
+	}
+	return FormatOptions{}
+}
+
 // Error returns the error string representation including the cause of this error.
 func (e *Error) Error() string {
 	var parts []string
@@ -14,7 +37,8 @@ func (e *Error) Error() string {
 		parts = append(parts, e.message)
 	}
 
-	if e.cause != nil {
+	// for errors with multiple causes, the additional causes are already part of the message (see Wrapf)
+	if e.cause != nil && !e.fullMessage {
 		parts = append(parts, e.cause.Error())
 	}
 
@@ -26,24 +50,63 @@ func (e *Error) Error() string {
 }
 
 // Format formats the error according to the format specifier.
+// %v prints the message and the fields of the whole chain sorted by their keys.
+// %+v prints every error of the chain with its fields in the order they were added and its stack trace,
+// frames that an error has in common with its cause are summarized (see SetFormatOptions).
 func (e *Error) Format(s fmt.State, verb rune) {
+	formatError(s, verb, e)
+}
+
+// formatError formats the error according to the format specifier, it is used by Error and MultiError.
+func formatError(s fmt.State, verb rune, err error) {
 	switch verb {
 	case 'v':
 		if !s.Flag('+') {
-			_, _ = io.WriteString(s, e.Error())
-			_, _ = writeFields(s, GetFields(e))
+			_, _ = io.WriteString(s, err.Error())
+			_, _ = writeFields(s, mapToFields(GetFields(err)))
 			return
 		}
-		stack := GetStack(e)
-		for i := range stack {
-			_, _ = writeError(s, &stack[i])
+		stack := GetStack(err)
+		if !currentFormatOptions().FullStack {
+			stack = ElideCommonFrames(stack)
 		}
+		_, _ = writeErrorStacks(s, stack)
 		return
 	case 's':
-		_, _ = io.WriteString(s, e.Error())
+		_, _ = io.WriteString(s, err.Error())
 	case 'q':
-		_, _ = fmt.Fprintf(s, "%q", e.Error())
+		_, _ = fmt.Fprintf(s, "%q", err.Error())
+	}
+}
+
+func writeErrorStacks(w io.Writer, stack []ErrorStack) (int, error) {
+	ww := &writer{Writer: w}
+	for i := range stack {
+		_, err := writeError(ww, &stack[i])
+		if err != nil {
+			return ww.n, err
+		}
+		_, err = writeCauses(ww, stack[i].Causes)
+		if err != nil {
+			return ww.n, err
+		}
 	}
+	return ww.n, nil
+}
+
+func writeCauses(w io.Writer, causes [][]ErrorStack) (int, error) {
+	ww := &writer{Writer: w}
+	for i, cause := range causes {
+		_, err := fmt.Fprintf(ww, "cause %d of %d:\n", i+1, len(causes))
+		if err != nil {
+			return ww.n, err
+		}
+		_, err = writeErrorStacks(&indentWriter{Writer: ww, indent: causeIndent, atLineStart: true}, cause)
+		if err != nil {
+			return ww.n, err
+		}
+	}
+	return ww.n, nil
 }
 
 func writeError(w io.Writer, errorStack *ErrorStack) (int, error) {
@@ -52,13 +115,25 @@ func writeError(w io.Writer, errorStack *ErrorStack) (int, error) {
 	if err != nil {
 		return ww.n, err
 	}
+	if errorStack.Code != "" {
+		if n > 0 {
+			_, err = io.WriteString(ww, " ")
+			if err != nil {
+				return ww.n, err
+			}
+		}
+		n, err = fmt.Fprintf(ww, "(code=%s)", errorStack.Code)
+		if err != nil {
+			return ww.n, err
+		}
+	}
 	if n > 0 {
 		_, err = io.WriteString(ww, "\n")
 		if err != nil {
 			return ww.n, err
 		}
 	}
-	n, err = writeFields(ww, errorStack.Fields)
+	n, err = writeFields(ww, errorStack.orderedFields())
 	if err != nil {
 		return ww.n, err
 	}
@@ -72,6 +147,18 @@ func writeError(w io.Writer, errorStack *ErrorStack) (int, error) {
 	if err != nil {
 		return ww.n, err
 	}
+	if errorStack.FramesInCommon > 0 {
+		if n > 0 {
+			_, err = io.WriteString(ww, "\n")
+			if err != nil {
+				return ww.n, err
+			}
+		}
+		n, err = fmt.Fprintf(ww, "... %d frames in common with cause", errorStack.FramesInCommon)
+		if err != nil {
+			return ww.n, err
+		}
+	}
 	if n > 0 {
 		_, err = io.WriteString(ww, "\n")
 		if err != nil {
@@ -106,26 +193,55 @@ func writeStackFrames(w io.Writer, frames []StackFrame) (int, error) {
 }
 
 func writeStackFrame(w io.Writer, frame *StackFrame) (int, error) {
+	if frame.Remote {
+		return fmt.Fprintf(w, "%s\n\t%s:%d (remote)", frame.Func, frame.File, frame.Line)
+	}
 	return fmt.Fprintf(w, "%s\n\t%s:%d", frame.Func, frame.File, frame.Line)
 }
 
-func writeFields(w io.Writer, fields map[string]any) (int, error) {
-	sizeOfFields := len(fields)
-	if sizeOfFields == 0 {
+func writeFields(w io.Writer, fields []Field) (int, error) {
+	if len(fields) == 0 {
 		return 0, nil
 	}
 
-	s := make([]string, 0, sizeOfFields)
-
-	for k := range fields {
-		s = append(s, k)
+	s := make([]string, len(fields))
+	for i, field := range fields {
+		s[i] = fmt.Sprintf("%s=%v", field.Key, field.Value)
 	}
-	sort.Strings(s)
+	return fmt.Fprint(w, "[", strings.Join(s, " "), "]")
+}
 
-	for i, k := range s {
-		s[i] = fmt.Sprintf("%s=%v", k, fields[k])
+const causeIndent = "    "
+
+// indentWriter prefixes every line that is written with indent.
+type indentWriter struct {
+	io.Writer
+	indent      string
+	atLineStart bool
+}
+
+func (w *indentWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if w.atLineStart {
+			if _, err := io.WriteString(w.Writer, w.indent); err != nil {
+				return written, err
+			}
+			w.atLineStart = false
+		}
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line = p[:i+1]
+			w.atLineStart = true
+		}
+		n, err := w.Writer.Write(line)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(line):]
 	}
-	return fmt.Fprint(w, "[", strings.Join(s, " "), "]")
+	return written, nil
 }
 
 type writer struct {
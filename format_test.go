@@ -73,6 +73,7 @@ func TestError_Format(t *testing.T) {
 		Equal(t, "error 2: error 1[k1=v1 k2=v2]", fmt.Sprintf("%v", err))
 	})
 	t.Run("extra verbose", func(t *testing.T) {
+		skipWithoutStack(t)
 		expected := fmt.Sprintf("error 2\n[k2=v2]\n%s\nerror 1\n[k1=v1]\n%s\n",
 			generateExpectedStack(t, filename, "TestError_Format00"),
 			generateExpectedStack(t, filename, "TestError_Format01"),
@@ -81,6 +82,93 @@ func TestError_Format(t *testing.T) {
 	})
 }
 
+func TestError_Format_FieldOrder(t *testing.T) {
+	err := serrors.New("some error").With("b", "1").With("a", "2")
+
+	t.Run("verbose sorts the fields", func(t *testing.T) {
+		Equal(t, "some error[a=2 b=1]", fmt.Sprintf("%v", err))
+	})
+	t.Run("extra verbose keeps the order of the fields", func(t *testing.T) {
+		Equal(t, true, strings.HasPrefix(fmt.Sprintf("%+v", err), "some error\n[b=1 a=2]\n"))
+	})
+}
+
+func TestError_Format_MultipleCauses(t *testing.T) {
+	skipWithoutStack(t)
+
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	err := serrors.Wrap(errors.Join( // [TestError_Format_MultipleCauses00]
+		serrors.New("error 1").With("k1", "v1"),        // [TestError_Format_MultipleCauses01]
+		serrors.Wrap(errors.New("error 3"), "error 2"), // [TestError_Format_MultipleCauses02]
+	), "some error")
+
+	indent := func(s string) string {
+		return "    " + strings.ReplaceAll(s, "\n", "\n    ")
+	}
+
+	expected := fmt.Sprintf("some error\n%s\ncause 1 of 2:\n%s\n%s\ncause 2 of 2:\n%s\n%s\n%s\n",
+		generateExpectedStack(t, filename, "TestError_Format_MultipleCauses00"),
+		indent("error 1\n[k1=v1]"),
+		indent(generateExpectedStack(t, filename, "TestError_Format_MultipleCauses01")),
+		indent("error 2"),
+		indent(generateExpectedStack(t, filename, "TestError_Format_MultipleCauses02")),
+		indent("error 3"),
+	)
+	Equal(t, expected, fmt.Sprintf("%+v", err))
+}
+
+func wrapInFormatHelper(err error) error {
+	return serrors.Wrap(err, "outer error") // [wrapInFormatHelper00]
+}
+
+func newFormatErrorWithCommonFrames() error {
+	return wrapInFormatHelper(serrors.New("inner error")) // [newFormatErrorWithCommonFrames00]
+}
+
+func TestError_Format_CommonFrames(t *testing.T) {
+	skipWithoutStack(t)
+
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	err := newFormatErrorWithCommonFrames() // [TestError_Format_CommonFrames00]
+
+	t.Run("elided", func(t *testing.T) {
+		expected := fmt.Sprintf("outer error\n%s\n... 2 frames in common with cause\ninner error\n%s\n",
+			generateExpectedStack(t, filename, "wrapInFormatHelper00"),
+			generateExpectedStack(t, filename, "newFormatErrorWithCommonFrames00", "TestError_Format_CommonFrames00"),
+		)
+		Equal(t, expected, fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("full stack", func(t *testing.T) {
+		serrors.SetFormatOptions(serrors.FormatOptions{FullStack: true})
+		defer serrors.SetFormatOptions(serrors.FormatOptions{})
+
+		expected := fmt.Sprintf("outer error\n%s\ninner error\n%s\n",
+			generateExpectedStack(t, filename,
+				"wrapInFormatHelper00", "newFormatErrorWithCommonFrames00", "TestError_Format_CommonFrames00"),
+			generateExpectedStack(t, filename, "newFormatErrorWithCommonFrames00", "TestError_Format_CommonFrames00"),
+		)
+		Equal(t, expected, fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("GetStack returns the full stack", func(t *testing.T) {
+		stack := serrors.GetStack(err)
+		Equal(t, 3, len(stack[0].StackTrace))
+		Equal(t, 0, stack[0].FramesInCommon)
+
+		elided := serrors.ElideCommonFrames(stack)
+		Equal(t, 1, len(elided[0].StackTrace))
+		Equal(t, 2, elided[0].FramesInCommon)
+		Equal(t, 2, len(elided[1].StackTrace))
+		Equal(t, 0, elided[1].FramesInCommon)
+		Equal(t, 3, len(stack[0].StackTrace))
+	})
+}
+
 func generateExpectedStack(t *testing.T, filename string, markers ...string) string {
 	parts := make([]string, len(markers))
 	for i, marker := range markers {
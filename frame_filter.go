@@ -0,0 +1,178 @@
+package serrors
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// FrameDecision is the result of a FrameFilter.
+type FrameDecision int
+
+const (
+	// KeepFrame keeps the frame in the stack.
+	KeepFrame FrameDecision = iota
+	// DropFrame removes the frame from the stack.
+	DropFrame
+	// KeepFrameAndStop keeps the frame and removes all frames that follow it.
+	KeepFrameAndStop
+)
+
+// FrameFilter decides whether a stack frame is kept in the stack of an error.
+// Frames of this package are always removed, before any FrameFilter is called.
+type FrameFilter func(frame StackFrame) FrameDecision
+
+// FrameFilterMode controls when the frame filters are applied.
+type FrameFilterMode int
+
+const (
+	// FilterAtRender applies the frame filters that are set when the stack of an error is resolved,
+	// e.g. by GetStack or when the error is formatted. This is the default.
+	FilterAtRender FrameFilterMode = iota
+	// FilterAtCapture applies the frame filters that are set when the error is created.
+	// The stack is resolved immediately, which makes creating errors more expensive.
+	FilterAtCapture
+)
+
+var (
+	frameFiltersMu  sync.Mutex
+	frameFilters    atomic.Pointer[[]FrameFilter]
+	frameFilterMode atomic.Int64
+
+	defaultFrameFilters = DefaultFrameFilters()
+)
+
+// DefaultFrameFilters returns the frame filters that are used if SetFrameFilters was not called,
+// these remove the frames of the runtime and the testing package.
+func DefaultFrameFilters() []FrameFilter {
+	return []FrameFilter{
+		FilterPrefix("runtime."),
+		FilterPrefix("testing."),
+	}
+}
+
+// SetFrameFilters replaces all frame filters with the passed in filters.
+// Use SetFrameFilters() without arguments to keep all frames, e.g. to see the frames of the
+// testing package, and SetFrameFilters(DefaultFrameFilters()...) to restore the defaults.
+func SetFrameFilters(filters ...FrameFilter) {
+	frameFiltersMu.Lock()
+	defer frameFiltersMu.Unlock()
+	filters = append([]FrameFilter(nil), filters...)
+	frameFilters.Store(&filters)
+}
+
+// AddFrameFilter adds a frame filter to the currently set frame filters.
+// Filters are called in the order they were added, the first filter that does not return KeepFrame
+// decides about the frame.
+func AddFrameFilter(filter FrameFilter) {
+	frameFiltersMu.Lock()
+	defer frameFiltersMu.Unlock()
+	current := currentFrameFilters()
+	filters := make([]FrameFilter, 0, len(current)+1)
+	filters = append(filters, current...)
+	filters = append(filters, filter)
+	frameFilters.Store(&filters)
+}
+
+// SetFrameFilterMode sets when the frame filters are applied, see FrameFilterMode.
+func SetFrameFilterMode(mode FrameFilterMode) {
+	frameFilterMode.Store(int64(mode))
+}
+
+func currentFrameFilters() []FrameFilter {
+	return *loadFrameFilters()
+}
+
+// loadFrameFilters returns the set frame filters, the pointer changes whenever the filters are changed.
+func loadFrameFilters() *[]FrameFilter {
+	if filters := frameFilters.Load(); filters != nil {
+		return filters
+	}
+	return &defaultFrameFilters
+}
+
+func currentFrameFilterMode() FrameFilterMode {
+	return FrameFilterMode(frameFilterMode.Load())
+}
+
+// FilterPrefix returns a FrameFilter that removes all frames whose function starts with one of
+// the prefixes, e.g. "github.com/my/project/middleware.".
+func FilterPrefix(prefixes ...string) FrameFilter {
+	return func(frame StackFrame) FrameDecision {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(frame.Func, prefix) {
+				return DropFrame
+			}
+		}
+		return KeepFrame
+	}
+}
+
+// FilterStdlib returns a FrameFilter that removes all frames of the standard library.
+// A frame belongs to the standard library if the first element of its package path
+// does not contain a dot, e.g. "net/http" or "runtime". Frames of the main package are kept.
+func FilterStdlib() FrameFilter {
+	return func(frame StackFrame) FrameDecision {
+		if isStdlibFunc(frame.Func) {
+			return DropFrame
+		}
+		return KeepFrame
+	}
+}
+
+// TrimAfterMain returns a FrameFilter that removes all frames after main.main.
+func TrimAfterMain() FrameFilter {
+	return func(frame StackFrame) FrameDecision {
+		if frame.Func == "main.main" {
+			return KeepFrameAndStop
+		}
+		return KeepFrame
+	}
+}
+
+func isStdlibFunc(function string) bool {
+	// the function is in the form of "path/to/pkg.Func" or "path/to/pkg.(*Type).Method"
+	pkgPath := function
+	lastSlash := strings.LastIndexByte(pkgPath, '/')
+	if i := strings.IndexByte(pkgPath[lastSlash+1:], '.'); i >= 0 {
+		pkgPath = pkgPath[:lastSlash+1+i]
+	}
+	if pkgPath == "main" {
+		return false
+	}
+	first, _, _ := strings.Cut(pkgPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// filterFrame returns the decision of the first filter that does not keep the frame.
+func filterFrame(filters []FrameFilter, frame StackFrame) FrameDecision {
+	for _, filter := range filters {
+		if decision := filter(frame); decision != KeepFrame {
+			return decision
+		}
+	}
+	return KeepFrame
+}
+
+// filterStackFrames applies the frame filters to frames that were not resolved from program counters.
+func filterStackFrames(frames []StackFrame) []StackFrame {
+	filters := currentFrameFilters()
+	var result []StackFrame
+	for _, frame := range frames {
+		if isInternalFunc(frame.Func) {
+			continue
+		}
+		decision := filterFrame(filters, frame)
+		if decision != DropFrame {
+			result = append(result, frame)
+		}
+		if decision == KeepFrameAndStop {
+			break
+		}
+	}
+	return result
+}
+
+func isInternalFunc(function string) bool {
+	return strings.HasPrefix(function, "github.com/Eun/serrors.")
+}
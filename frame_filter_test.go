@@ -0,0 +1,124 @@
+//go:build !serrors_without_stack
+// +build !serrors_without_stack
+
+package serrors_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func hasFrame(err error, prefix string) bool {
+	for _, stack := range serrors.GetStack(err) {
+		for _, frame := range stack.StackTrace {
+			if strings.HasPrefix(frame.Func, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newErrorInSort() *serrors.Error {
+	var err *serrors.Error
+	values := []int{2, 1}
+	sort.Slice(values, func(i, j int) bool {
+		if err == nil {
+			err = serrors.New("error in sort")
+		}
+		return values[i] < values[j]
+	})
+	return err
+}
+
+func TestFrameFilters(t *testing.T) {
+	defer serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+
+	t.Run("default filters", func(t *testing.T) {
+		err := serrors.New("some error")
+		Equal(t, false, hasFrame(err, "testing."))
+		Equal(t, false, hasFrame(err, "runtime."))
+		Equal(t, false, hasFrame(err, "github.com/Eun/serrors."))
+	})
+
+	t.Run("no filters", func(t *testing.T) {
+		serrors.SetFrameFilters()
+		defer serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+
+		err := serrors.New("some error")
+		Equal(t, true, hasFrame(err, "testing."))
+		Equal(t, false, hasFrame(err, "github.com/Eun/serrors."))
+	})
+
+	t.Run("AddFrameFilter", func(t *testing.T) {
+		serrors.AddFrameFilter(serrors.FilterPrefix("github.com/Eun/serrors_test.newErrorWithBuilder"))
+		defer serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+
+		err := newErrorWithBuilder(serrors.NewBuilder())
+		Equal(t, false, hasFrame(err, "github.com/Eun/serrors_test.newErrorWithBuilder"))
+		Equal(t, true, hasFrame(err, "github.com/Eun/serrors_test.TestFrameFilters"))
+	})
+
+	t.Run("FilterStdlib", func(t *testing.T) {
+		Equal(t, true, hasFrame(newErrorInSort(), "sort."))
+
+		serrors.AddFrameFilter(serrors.FilterStdlib())
+		defer serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+
+		err := newErrorInSort()
+		Equal(t, false, hasFrame(err, "sort."))
+		Equal(t, true, hasFrame(err, "github.com/Eun/serrors_test.newErrorInSort"))
+	})
+
+	t.Run("KeepFrameAndStop", func(t *testing.T) {
+		serrors.AddFrameFilter(func(frame serrors.StackFrame) serrors.FrameDecision {
+			if strings.HasPrefix(frame.Func, "github.com/Eun/serrors_test.newErrorWithBuilder") {
+				return serrors.KeepFrameAndStop
+			}
+			return serrors.KeepFrame
+		})
+		defer serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+
+		stack := serrors.GetStack(newErrorWithBuilder(serrors.NewBuilder()))
+		Equal(t, 1, len(stack[0].StackTrace))
+		Equal(t, "github.com/Eun/serrors_test.newErrorWithBuilder", stack[0].StackTrace[0].Func)
+	})
+
+	t.Run("TrimAfterMain", func(t *testing.T) {
+		filter := serrors.TrimAfterMain()
+		Equal(t, serrors.KeepFrameAndStop, filter(serrors.StackFrame{Func: "main.main"}))
+		Equal(t, serrors.KeepFrame, filter(serrors.StackFrame{Func: "main.run"}))
+	})
+
+	t.Run("FilterStdlib decisions", func(t *testing.T) {
+		filter := serrors.FilterStdlib()
+		Equal(t, serrors.DropFrame, filter(serrors.StackFrame{Func: "fmt.Errorf"}))
+		Equal(t, serrors.DropFrame, filter(serrors.StackFrame{Func: "net/http.(*Server).Serve"}))
+		Equal(t, serrors.KeepFrame, filter(serrors.StackFrame{Func: "main.main"}))
+		Equal(t, serrors.KeepFrame, filter(serrors.StackFrame{Func: "github.com/Eun/serrors_test.TestFrameFilters"}))
+	})
+}
+
+func TestFrameFilterMode(t *testing.T) {
+	defer serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+
+	t.Run("FilterAtRender", func(t *testing.T) {
+		serrors.SetFrameFilters()
+		err := serrors.New("some error")
+		serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+		Equal(t, false, hasFrame(err, "testing."))
+	})
+
+	t.Run("FilterAtCapture", func(t *testing.T) {
+		serrors.SetFrameFilterMode(serrors.FilterAtCapture)
+		defer serrors.SetFrameFilterMode(serrors.FilterAtRender)
+
+		serrors.SetFrameFilters()
+		err := serrors.New("some error")
+		serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+		Equal(t, true, hasFrame(err, "testing."))
+	})
+}
@@ -0,0 +1,122 @@
+package serrors
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// WorkerField is the key of the field that holds the label or the index of the worker that failed.
+const WorkerField = "worker"
+
+// Group runs functions in goroutines and collects their errors.
+// Panics in the functions are recovered and converted into errors (see Recover),
+// every error is tagged with the field WorkerField.
+// The zero value is ready to use, a Group must not be copied after first use.
+type Group struct {
+	// Recoverer is used to recover panics of the functions.
+	Recoverer Recoverer
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	next     int
+	firstErr error
+	errs     []groupError
+}
+
+type groupError struct {
+	index int
+	err   error
+}
+
+// GroupWithContext returns a new Group and a context derived from ctx.
+// The context is canceled when the first function returns an error or when Wait or WaitAll returns.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go runs fn in a new goroutine, a returned error is tagged with the index of the worker,
+// which is the number of functions that were started before by Go or GoWithLabel.
+func (g *Group) Go(fn func() error) {
+	g.mu.Lock()
+	index := g.next
+	g.next++
+	g.mu.Unlock()
+	g.start(index, index, fn)
+}
+
+// GoWithLabel runs fn in a new goroutine, a returned error is tagged with the label.
+func (g *Group) GoWithLabel(label string, fn func() error) {
+	g.mu.Lock()
+	index := g.next
+	g.next++
+	g.mu.Unlock()
+	g.start(index, label, fn)
+}
+
+func (g *Group) start(index int, worker any, fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		err := g.run(fn)
+		if err == nil {
+			return
+		}
+		err = &Error{
+			cause:  err,
+			fields: []Field{{Key: WorkerField, Value: worker}},
+		}
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.firstErr == nil {
+			g.firstErr = err
+			if g.cancel != nil {
+				g.cancel()
+			}
+		}
+		g.errs = append(g.errs, groupError{index: index, err: err})
+	}()
+}
+
+func (g *Group) run(fn func() error) (err error) {
+	defer g.Recoverer.Recover(&err)
+	return fn()
+}
+
+// Wait blocks until all functions have returned and returns the first error that occurred.
+func (g *Group) Wait() error {
+	g.wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.firstErr
+}
+
+// WaitAll blocks until all functions have returned and returns all errors as a MultiError,
+// ordered by the order the functions were started in. It returns nil if no error occurred.
+func (g *Group) WaitAll() error {
+	g.wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	sort.Slice(g.errs, func(i, j int) bool {
+		return g.errs[i].index < g.errs[j].index
+	})
+	errs := make([]error, len(g.errs))
+	for i := range g.errs {
+		errs[i] = g.errs[i].err
+	}
+	return NewMultiError(errs...)
+}
+
+func (g *Group) wait() {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
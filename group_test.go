@@ -0,0 +1,77 @@
+package serrors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("no error", func(t *testing.T) {
+		var g serrors.Group
+		g.Go(func() error { return nil })
+		Nil(t, g.Wait())
+		Nil(t, g.WaitAll())
+	})
+
+	t.Run("Wait returns the first error", func(t *testing.T) {
+		var g serrors.Group
+		g.Go(func() error { return nil })
+		g.Go(func() error { return serrors.New("some error").With("k", "v") })
+		err := g.Wait()
+		Equal(t, "some error", err.Error())
+		Equal(t, []serrors.Field{
+			{Key: serrors.WorkerField, Value: 1},
+			{Key: "k", Value: "v"},
+		}, serrors.GetOrderedFields(err))
+	})
+
+	t.Run("WaitAll returns all errors", func(t *testing.T) {
+		var g serrors.Group
+		g.Go(func() error { return serrors.New("error 1") })
+		g.Go(func() error { return nil })
+		g.GoWithLabel("io", func() error { return io.EOF })
+		err := g.WaitAll()
+		Equal(t, "2 errors occurred: error 1; EOF", err.Error())
+		Equal(t, true, errors.Is(err, io.EOF))
+
+		stack := serrors.GetStack(err)
+		Equal(t, 2, len(stack[len(stack)-1].Causes))
+		Equal(t, map[string]any{serrors.WorkerField: 0}, stack[len(stack)-1].Causes[0][0].Fields)
+		Equal(t, map[string]any{serrors.WorkerField: "io"}, stack[len(stack)-1].Causes[1][0].Fields)
+
+		formatted := fmt.Sprintf("%+v", err)
+		Equal(t, true, strings.HasPrefix(formatted, "2 errors occurred\ncause 1 of 2:\n"))
+		Equal(t, true, strings.Contains(formatted, "cause 1 of 2:\n    [worker=0]\n    error 1\n"))
+		Equal(t, true, strings.Contains(formatted, "cause 2 of 2:\n    [worker=io]\n    EOF\n"))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var g serrors.Group
+		g.GoWithLabel("panicking", func() error {
+			panic("boom")
+		})
+		err := g.Wait()
+		Equal(t, "panic: boom", err.Error())
+		Equal(t, map[string]any{
+			serrors.WorkerField: "panicking",
+			serrors.PanicField:  "boom",
+		}, serrors.GetFields(err))
+	})
+
+	t.Run("context is canceled on error", func(t *testing.T) {
+		g, ctx := serrors.GroupWithContext(context.Background())
+		g.Go(func() error { return io.EOF })
+		g.Go(func() error {
+			<-ctx.Done()
+			return nil
+		})
+		Equal(t, true, errors.Is(g.Wait(), io.EOF))
+		NotNil(t, ctx.Err())
+	})
+}
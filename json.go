@@ -0,0 +1,195 @@
+package serrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// jsonError is the json representation of an error in the error tree.
+type jsonError struct {
+	ErrorMessage string `json:"error_message"`
+	// FullMessage is set when ErrorMessage already contains the text of the causes.
+	FullMessage bool         `json:"full_message,omitempty"`
+	Code        Code         `json:"code,omitempty"`
+	Fields      jsonFields   `json:"fields"`
+	StackTrace  []StackFrame `json:"stack_trace"`
+	Cause       *jsonError   `json:"cause,omitempty"`
+	Causes      []*jsonError `json:"causes,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// The whole error tree is encoded, including messages, codes, fields and stack frames,
+// so it can be decoded into an Error using json.Unmarshal.
+// Sensitive field values are redacted.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONError(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It reconstructs an error tree that was encoded using Error.MarshalJSON.
+// Error(), GetFields, GetCode and GetStack behave like they did for the original error,
+// all stack frames are marked as remote (see StackFrame.Remote).
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return err
+	}
+	*e = *je.toError()
+	return nil
+}
+
+func newJSONError(err error) *jsonError {
+	var je *jsonError
+	if e, ok := err.(*Error); ok {
+		je = &jsonError{
+			ErrorMessage: e.message,
+			FullMessage:  e.fullMessage,
+			Code:         e.code,
+			Fields:       e.fields,
+			StackTrace:   e.stackFrames(),
+			Cause:        nil,
+			Causes:       nil,
+		}
+	} else {
+		errorStack := buildErrorStack(err)
+		je = &jsonError{
+			ErrorMessage: err.Error(),
+			FullMessage:  true,
+			Code:         "",
+			Fields:       ownFields(err),
+			StackTrace:   errorStack.StackTrace,
+			Cause:        nil,
+			Causes:       nil,
+		}
+	}
+
+	causes := unwrapErrors(err)
+	switch len(causes) {
+	case 0:
+	case 1:
+		je.Cause = newJSONError(causes[0])
+	default:
+		je.Causes = make([]*jsonError, len(causes))
+		for i, cause := range causes {
+			je.Causes[i] = newJSONError(cause)
+		}
+	}
+	return je
+}
+
+func (je *jsonError) toError() *Error {
+	var frames []StackFrame
+	if len(je.StackTrace) > 0 {
+		frames = make([]StackFrame, len(je.StackTrace))
+		for i, frame := range je.StackTrace {
+			frame.Remote = true
+			frames[i] = frame
+		}
+	}
+
+	e := &Error{
+		message:     je.ErrorMessage,
+		fields:      je.Fields,
+		code:        je.Code,
+		frames:      frames,
+		fullMessage: je.FullMessage,
+	}
+
+	switch {
+	case len(je.Causes) > 0:
+		errs := make([]error, len(je.Causes))
+		for i, cause := range je.Causes {
+			errs[i] = cause.toError()
+		}
+		e.cause = errs[0]
+		e.causes = errs
+	case je.Cause != nil:
+		e.cause = je.Cause.toError()
+	}
+	return e
+}
+
+// jsonFields encodes fields as a json object, the order of the fields is preserved.
+type jsonFields []Field
+
+func (f jsonFields) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			// the value cannot be represented in json, use its text representation
+			value, err = json.Marshal(fmt.Sprintf("%v", field.Value))
+			if err != nil {
+				return nil, err
+			}
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (f *jsonFields) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		*f = nil
+		return nil
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("unable to decode fields: expected an object, got %v", token)
+	}
+	fields := jsonFields{}
+	for dec.More() {
+		token, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unable to decode fields: expected a key, got %v", token)
+		}
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+	*f = fields
+	return nil
+}
+
+// mapToFields converts the map into fields that are sorted by their key.
+func mapToFields(m map[string]any) []Field {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]Field, len(keys))
+	for i, k := range keys {
+		fields[i] = Field{Key: k, Value: m[k]}
+	}
+	return fields
+}
@@ -0,0 +1,147 @@
+package serrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/Eun/serrors"
+)
+
+var (
+	_ json.Marshaler   = &serrors.Error{} // make sure we implement the json.Marshaler interface
+	_ json.Unmarshaler = &serrors.Error{} // make sure we implement the json.Unmarshaler interface
+)
+
+func markRemote(stack []serrors.ErrorStack) []serrors.ErrorStack {
+	for i := range stack {
+		for j := range stack[i].StackTrace {
+			stack[i].StackTrace[j].Remote = true
+		}
+		for _, cause := range stack[i].Causes {
+			markRemote(cause)
+		}
+	}
+	return stack
+}
+
+func roundTrip(t *testing.T, err *serrors.Error) *serrors.Error {
+	buf, jsonErr := json.Marshal(err)
+	Nil(t, jsonErr)
+
+	var decoded *serrors.Error
+	Nil(t, json.Unmarshal(buf, &decoded))
+	NotNil(t, decoded)
+	return decoded
+}
+
+func TestError_JSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		error *serrors.Error
+	}{
+		{
+			name:  "simple",
+			error: serrors.New("some error").With("k2", "v2").With("k1", 1),
+		},
+		{
+			name:  "empty",
+			error: serrors.New(""),
+		},
+		{
+			name: "wrapped",
+			error: serrors.Wrap(
+				serrors.New("error 1").With("k", "inner").With("k1", "v1").WithCode(codeNotFound),
+				"error 2",
+			).With("k", "outer").WithCode(codeConflict),
+		},
+		{
+			name: "third party errors",
+			error: serrors.Wrap(
+				fmt.Errorf("error 2: %w", pkgerrors.Wrap(errors.New("error 4"), "error 3")),
+				"error 1",
+			),
+		},
+		{
+			name:  "%w in the middle",
+			error: serrors.Errorf("error 1 (%w) here", serrors.New("error 2").With("k", "v")),
+		},
+		{
+			name: "joined",
+			error: serrors.Wrap(errors.Join(
+				serrors.New("error 2").With("k", "v2"),
+				errors.New("error 3"),
+			), "error 1"),
+		},
+		{
+			name:  "Wrapf with %w",
+			error: serrors.Wrapf(errors.New("error 2"), "error 1 %w", errors.New("error 3")),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			decoded := roundTrip(t, tc.error)
+
+			Equal(t, tc.error.Error(), decoded.Error())
+			Equal(t, fmt.Sprintf("%v", tc.error), fmt.Sprintf("%v", decoded))
+			Equal(t, serrors.GetCode(tc.error), serrors.GetCode(decoded))
+			// numbers are decoded as float64, so compare the json representation
+			Equal(t, toJSONValue(t, serrors.GetFields(tc.error)), toJSONValue(t, serrors.GetFields(decoded)))
+			Equal(t, len(serrors.GetOrderedFields(tc.error)), len(serrors.GetOrderedFields(decoded)))
+			for i, field := range serrors.GetOrderedFields(tc.error) {
+				Equal(t, field.Key, serrors.GetOrderedFields(decoded)[i].Key)
+			}
+			CompareErrorStack(t, markRemote(serrors.GetStack(tc.error)), serrors.GetStack(decoded))
+		})
+	}
+}
+
+func TestError_JSON_Format(t *testing.T) {
+	skipWithoutStack(t)
+
+	err := serrors.New("some error")
+	decoded := roundTrip(t, err)
+
+	stack := serrors.GetStack(err)
+	Equal(t, 1, len(stack))
+	Equal(t, 1, len(stack[0].StackTrace))
+	frame := stack[0].StackTrace[0]
+
+	expected := fmt.Sprintf("some error\n%s\n\t%s:%d (remote)\n", frame.Func, frame.File, frame.Line)
+	Equal(t, expected, fmt.Sprintf("%+v", decoded))
+}
+
+func TestError_JSON_Encoding(t *testing.T) {
+	err := serrors.Wrap(
+		serrors.New("error 1").WithSecret("token", "secret"),
+		"error 2",
+	).With("b", 1).With("a", make(chan int)).WithCode(codeNotFound)
+
+	buf, jsonErr := json.Marshal(err)
+	Nil(t, jsonErr)
+
+	var decoded map[string]any
+	Nil(t, json.Unmarshal(buf, &decoded))
+	Equal(t, "error 2", decoded["error_message"])
+	Equal(t, "not_found", decoded["code"])
+	Equal(t, float64(1), decoded["fields"].(map[string]any)["b"])
+	NotNil(t, decoded["fields"].(map[string]any)["a"])
+	Equal(t, "error 1", decoded["cause"].(map[string]any)["error_message"])
+	Equal(t, "[REDACTED]", decoded["cause"].(map[string]any)["fields"].(map[string]any)["token"])
+
+	// order of fields is preserved
+	Equal(t, true, strings.Index(string(buf), `"b":`) < strings.Index(string(buf), `"a":`))
+}
+
+func TestError_JSON_InvalidInput(t *testing.T) {
+	var decoded *serrors.Error
+	NotNil(t, json.Unmarshal([]byte(`{"error_message": 1}`), &decoded))
+	NotNil(t, json.Unmarshal([]byte(`{"fields": []}`), &decoded))
+	NotNil(t, json.Unmarshal([]byte(`{"fields": {"a": }}`), &decoded))
+}
@@ -0,0 +1,136 @@
+package serrors
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// MultiError aggregates multiple errors, e.g. the errors of a validation or a batch job.
+// Its fields apply to all of its errors, GetFields returns them with precedence over the fields
+// of the aggregated errors.
+// errors.Is and errors.As search all aggregated errors.
+// A nil *MultiError is an empty MultiError, so it can be used without initialization:
+//
+//	var errs *serrors.MultiError
+//	for _, item := range items {
+//		errs = errs.Append(validate(item))
+//	}
+//	return errs.ErrorOrNil()
+type MultiError struct {
+	errs   []error
+	fields []Field
+}
+
+// NewMultiError creates a new MultiError holding the passed in errors, nil errors are ignored.
+func NewMultiError(errs ...error) *MultiError {
+	return (*MultiError)(nil).Append(errs...)
+}
+
+// Append adds the errors to the MultiError and returns it, nil errors are ignored.
+// If the MultiError is nil a new MultiError will be returned.
+// Unlike With, Append modifies the MultiError, so it must not be called concurrently.
+func (m *MultiError) Append(errs ...error) *MultiError {
+	if m == nil {
+		m = &MultiError{}
+	}
+	for _, err := range errs {
+		if err != nil {
+			m.errs = append(m.errs, err)
+		}
+	}
+	return m
+}
+
+// Len returns the number of errors.
+func (m *MultiError) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.errs)
+}
+
+// Errors returns a copy of the aggregated errors.
+func (m *MultiError) Errors() []error {
+	if m.Len() == 0 {
+		return nil
+	}
+	return append([]error(nil), m.errs...)
+}
+
+// Filter returns a new MultiError with the same fields, that only holds the errors for which keep
+// returns true.
+func (m *MultiError) Filter(keep func(err error) bool) *MultiError {
+	result := &MultiError{}
+	if m == nil {
+		return result
+	}
+	result.fields = m.fields
+	for _, err := range m.errs {
+		if keep(err) {
+			result.errs = append(result.errs, err)
+		}
+	}
+	return result
+}
+
+// With returns a copy of the MultiError with the field key set to value.
+// The MultiError itself is not modified.
+func (m *MultiError) With(key string, value any) *MultiError {
+	var clone MultiError
+	if m != nil {
+		clone = *m
+		clone.errs = m.Errors()
+	}
+	clone.fields = withField(clone.fields, key, value)
+	return &clone
+}
+
+// ErrorOrNil returns nil if the MultiError holds no errors, otherwise it returns the MultiError.
+// Use it to return a MultiError as an error, to avoid returning a non nil error interface holding
+// an empty MultiError.
+func (m *MultiError) ErrorOrNil() error {
+	if m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+// Unwrap returns the aggregated errors, it provides compatibility for errors.Is and errors.As.
+func (m *MultiError) Unwrap() []error {
+	return m.Errors()
+}
+
+// Error returns the number of errors followed by their messages.
+func (m *MultiError) Error() string {
+	if m.Len() == 0 {
+		return "no errors"
+	}
+	messages := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		messages[i] = err.Error()
+	}
+	return m.summary() + ": " + strings.Join(messages, "; ")
+}
+
+// Format formats the error according to the format specifier.
+// %v prints the message and the fields of all errors sorted by their keys (see GetFields).
+// %+v prints every aggregated error with its message, its fields and its stack.
+func (m *MultiError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, m)
+}
+
+// LogValue implements slog.LogValuer.
+// The error will be rendered like an Error, the output can be configured with SetLogOptions.
+func (m *MultiError) LogValue() slog.Value {
+	return LogValue(m, currentLogOptions())
+}
+
+// summary returns the number of errors, it is the message of the MultiError in GetStack.
+func (m *MultiError) summary() string {
+	if m.Len() == 1 {
+		return "1 error occurred"
+	}
+	return strconv.Itoa(m.Len()) + " errors occurred"
+}
@@ -0,0 +1,110 @@
+package serrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func TestMultiError(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		var errs *serrors.MultiError
+		errs = errs.Append(nil)
+		Equal(t, 0, errs.Len())
+		Nil(t, errs.ErrorOrNil())
+		Nil(t, errs.Errors())
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		var errs *serrors.MultiError
+		errs = errs.Append(errors.New("error 1"), nil)
+		Equal(t, 1, errs.Len())
+		Equal(t, "1 error occurred: error 1", errs.Error())
+
+		errs = errs.Append(errors.New("error 2"))
+		Equal(t, 2, errs.Len())
+		Equal(t, "2 errors occurred: error 1; error 2", errs.Error())
+		Equal(t, errs, errs.ErrorOrNil())
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		errs := serrors.NewMultiError(io.EOF, errors.New("error 1"), io.ErrUnexpectedEOF).With("k", "v")
+		filtered := errs.Filter(func(err error) bool {
+			return !errors.Is(err, io.EOF)
+		})
+		Equal(t, 3, errs.Len())
+		Equal(t, 2, filtered.Len())
+		Equal(t, "2 errors occurred: error 1; unexpected EOF", filtered.Error())
+		Equal(t, map[string]any{"k": "v"}, serrors.GetFields(filtered))
+	})
+
+	t.Run("With does not modify the MultiError", func(t *testing.T) {
+		errs := serrors.NewMultiError(io.EOF)
+		withField := errs.With("k", "v")
+		Nil(t, serrors.GetFields(errs))
+		Equal(t, map[string]any{"k": "v"}, serrors.GetFields(withField))
+
+		withField.Append(io.ErrUnexpectedEOF)
+		Equal(t, 1, errs.Len())
+	})
+
+	t.Run("errors.Is and errors.As", func(t *testing.T) {
+		serr := serrors.New("error 1")
+		errs := serrors.NewMultiError(serr, fmt.Errorf("wrapped: %w", io.EOF))
+		Equal(t, true, errors.Is(errs, io.EOF))
+		Equal(t, true, errors.Is(errs, serr))
+
+		var target *serrors.Error
+		Equal(t, true, errors.As(errs, &target))
+		Equal(t, serr, target)
+	})
+
+	t.Run("fields apply to all errors", func(t *testing.T) {
+		errs := serrors.NewMultiError(
+			serrors.New("error 1").With("k", "inner").With("k1", "v1"),
+			serrors.New("error 2").With("k2", "v2"),
+		).With("k", "outer")
+		Equal(t, []serrors.Field{
+			{Key: "k", Value: "outer"},
+			{Key: "k1", Value: "v1"},
+			{Key: "k2", Value: "v2"},
+		}, serrors.GetOrderedFields(errs))
+	})
+
+	t.Run("Errorf", func(t *testing.T) {
+		errs := serrors.NewMultiError(io.EOF, io.ErrUnexpectedEOF).With("k", "v")
+		err := serrors.Errorf("batch failed: %w", errs)
+		Equal(t, "batch failed: 2 errors occurred: EOF; unexpected EOF", err.Error())
+		Equal(t, true, errors.Is(err, io.ErrUnexpectedEOF))
+	})
+}
+
+func TestMultiError_Format(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	errs := serrors.NewMultiError(
+		serrors.New("error 1").With("k1", "v1"), // [TestMultiError_Format00]
+		errors.New("error 2"),
+	).With("batch", 1)
+
+	indent := func(s string) string {
+		return "    " + strings.ReplaceAll(s, "\n", "\n    ")
+	}
+
+	Equal(t, "2 errors occurred: error 1; error 2[batch=1 k1=v1]", fmt.Sprintf("%v", errs))
+	Equal(t, "2 errors occurred: error 1; error 2", fmt.Sprintf("%s", errs))
+
+	skipWithoutStack(t)
+	expected := fmt.Sprintf("2 errors occurred\n[batch=1]\ncause 1 of 2:\n%s\n%s\ncause 2 of 2:\n%s\n",
+		indent("error 1\n[k1=v1]"),
+		indent(generateExpectedStack(t, filename, "TestMultiError_Format00")),
+		indent("error 2"),
+	)
+	Equal(t, expected, fmt.Sprintf("%+v", errs))
+}
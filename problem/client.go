@@ -0,0 +1,130 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Eun/serrors"
+)
+
+// maxBodySize is the maximum number of bytes that are read from a response body.
+const maxBodySize = 1 << 20
+
+// Error returns the detail of the problem, or its title if there is no detail.
+// This makes it possible to use a Problem as the cause of an error.
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	if p.Title != "" {
+		return p.Title
+	}
+	return http.StatusText(p.Status)
+}
+
+// FromResponse returns an error for responses with a non 2xx status code, it returns nil otherwise.
+// If the response contains problem details (application/problem+json) the parsed Problem will be the
+// cause of the error, otherwise the text of the body is used as the cause.
+// If the body could not be read, the read error is the cause.
+// The error contains the fields "status", "method" and "url", as well as the type, the instance and
+// all extension members of the problem. The "code" extension member is used as the code of the error.
+// The password of the url is redacted, the query of the url is added as the sensitive field "query".
+// The body of the response is read but not closed.
+func FromResponse(resp *http.Response) error {
+	serrors.Helper()
+	if resp == nil {
+		return serrors.New("response is nil")
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return newResponseError(resp, err, nil)
+		}
+	}
+
+	if isProblem(resp.Header.Get("Content-Type")) {
+		var p Problem
+		if err := json.Unmarshal(body, &p); err == nil {
+			return newResponseError(resp, &p, &p)
+		}
+	}
+
+	var cause error
+	if text := strings.TrimSpace(string(body)); text != "" {
+		cause = errors.New(text)
+	}
+	return newResponseError(resp, cause, nil)
+}
+
+func newResponseError(resp *http.Response, cause error, p *Problem) *serrors.Error {
+	serrors.Helper()
+	status := resp.Status
+	if status == "" {
+		status = http.StatusText(resp.StatusCode)
+	}
+	serr := serrors.Wrap(cause, "request failed with status "+status).
+		With("status", resp.StatusCode)
+	serr = withRequestFields(serr, resp)
+	if p == nil {
+		return serr
+	}
+
+	if p.Type != "" && p.Type != DefaultType {
+		serr = serr.With("type", p.Type)
+	}
+	if p.Instance != "" {
+		serr = serr.With("instance", p.Instance)
+	}
+	keys := make([]string, 0, len(p.Extensions))
+	for k := range p.Extensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if k == "code" {
+			if code, ok := p.Extensions[k].(string); ok {
+				serr = serr.WithCode(serrors.Code(code))
+				continue
+			}
+		}
+		if isReservedMember(k) || k == "method" || k == "url" || k == "query" {
+			continue
+		}
+		serr = serr.With(k, p.Extensions[k])
+	}
+	return serr
+}
+
+func withRequestFields(serr *serrors.Error, resp *http.Response) *serrors.Error {
+	if resp.Request == nil {
+		return serr
+	}
+	serr = serr.With("method", resp.Request.Method)
+	if resp.Request.URL != nil {
+		// the query and the userinfo might contain credentials
+		u := *resp.Request.URL
+		u.RawQuery = ""
+		u.ForceQuery = false
+		serr = serr.With("url", u.Redacted())
+		if resp.Request.URL.RawQuery != "" {
+			serr = serr.WithSecret("query", resp.Request.URL.RawQuery)
+		}
+	}
+	return serr
+}
+
+func isProblem(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == ContentType
+}
@@ -0,0 +1,18 @@
+//go:build !serrors_without_stack
+// +build !serrors_without_stack
+
+package problem_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func TestFromResponse_Stack(t *testing.T) {
+	_, err := get(t, func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "user not found", http.StatusNotFound)
+	})
+	Equal(t, "github.com/Eun/serrors/problem_test.get", serrors.GetStack(err)[0].StackTrace[0].Func)
+}
@@ -0,0 +1,138 @@
+package problem_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Eun/serrors"
+	"github.com/Eun/serrors/problem"
+)
+
+func get(t *testing.T, handler http.HandlerFunc) (string, error) {
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/users/joe", http.NoBody)
+	Nil(t, err)
+	resp, err := server.Client().Do(req)
+	Nil(t, err)
+	defer resp.Body.Close()
+
+	return server.URL + "/users/joe", problem.FromResponse(resp)
+}
+
+func TestFromResponse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		_, err := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		Nil(t, err)
+	})
+
+	t.Run("problem", func(t *testing.T) {
+		w := &problem.Writer{
+			TypeBaseURI:  "https://example.com/problems/",
+			PublicFields: []string{"user"},
+			CodeStatus: map[serrors.Code]int{
+				codeNotFound: http.StatusNotFound,
+			},
+		}
+		url, err := get(t, func(rw http.ResponseWriter, r *http.Request) {
+			w.Write(rw, r, serrors.New("user not found").WithCode(codeNotFound).With("user", "joe"))
+		})
+
+		Equal(t, "request failed with status 404 Not Found: user not found", err.Error())
+		Equal(t, codeNotFound, serrors.GetCode(err))
+		Equal(t, true, errors.Is(err, codeNotFound))
+		Equal(t, map[string]any{
+			"status":   http.StatusNotFound,
+			"method":   http.MethodGet,
+			"url":      url,
+			"type":     "https://example.com/problems/not_found",
+			"instance": "/users/joe",
+			"user":     "joe",
+		}, serrors.GetFields(err))
+
+		var p *problem.Problem
+		Equal(t, true, errors.As(err, &p))
+		Equal(t, "user not found", p.Detail)
+		Equal(t, http.StatusNotFound, p.Status)
+	})
+
+	t.Run("credentials in url", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		host := strings.TrimPrefix(server.URL, "http://")
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			"http://joe:password@"+host+"/users/joe?token=secret", http.NoBody)
+		Nil(t, err)
+		resp, err := server.Client().Do(req)
+		Nil(t, err)
+		defer resp.Body.Close()
+
+		err = problem.FromResponse(resp)
+		fields := serrors.GetFields(err)
+		Equal(t, "http://joe:xxxxx@"+host+"/users/joe", fields["url"])
+		Equal(t, serrors.Sensitive("token=secret"), fields["query"])
+		Equal(t, false, strings.Contains(fmt.Sprintf("%v", err), "secret"))
+		Equal(t, false, strings.Contains(fmt.Sprintf("%v", err), "password"))
+	})
+
+	t.Run("problem without detail", func(t *testing.T) {
+		_, err := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", problem.ContentType+"; charset=utf-8")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = io.WriteString(w, `{"title":"Conflict","status":409}`)
+		})
+		Equal(t, "request failed with status 409 Conflict: Conflict", err.Error())
+	})
+
+	t.Run("text body", func(t *testing.T) {
+		url, err := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "something went wrong", http.StatusBadGateway)
+		})
+
+		Equal(t, "request failed with status 502 Bad Gateway: something went wrong", err.Error())
+		Equal(t, map[string]any{
+			"status": http.StatusBadGateway,
+			"method": http.MethodGet,
+			"url":    url,
+		}, serrors.GetFields(err))
+	})
+
+	t.Run("invalid problem", func(t *testing.T) {
+		_, err := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", problem.ContentType)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `not json`)
+		})
+		Equal(t, "request failed with status 400 Bad Request: not json", err.Error())
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		_, err := get(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		Equal(t, "request failed with status 500 Internal Server Error", err.Error())
+		Nil(t, errors.Unwrap(err))
+	})
+
+	t.Run("response without request", func(t *testing.T) {
+		err := problem.FromResponse(&http.Response{StatusCode: http.StatusNotFound})
+		Equal(t, "request failed with status Not Found", err.Error())
+		Equal(t, map[string]any{"status": http.StatusNotFound}, serrors.GetFields(err))
+	})
+
+	t.Run("nil response", func(t *testing.T) {
+		Equal(t, "response is nil", problem.FromResponse(nil).Error())
+	})
+}
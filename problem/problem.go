@@ -0,0 +1,103 @@
+// Package problem converts errors into RFC 7807 problem details (application/problem+json)
+// and problem details back into errors.
+package problem
+
+import (
+	"encoding/json"
+)
+
+// ContentType is the media type of problem details.
+const ContentType = "application/problem+json"
+
+// DefaultType is the type that is used when the problem has no specific type.
+const DefaultType = "about:blank"
+
+// Problem is a RFC 7807 problem details object.
+type Problem struct {
+	// Type is a URI reference that identifies the problem type.
+	Type string
+	// Title is a short, human-readable summary of the problem type.
+	Title string
+	// Status is the http status code.
+	Status int
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is a URI reference that identifies the specific occurrence of the problem.
+	Instance string
+	// Extensions holds additional members of the problem.
+	Extensions map[string]any
+}
+
+// isReservedMember reports whether the name is a member that is defined by RFC 7807.
+func isReservedMember(name string) bool {
+	switch name {
+	case "type", "title", "status", "detail", "instance":
+		return true
+	}
+	return false
+}
+
+// MarshalJSON implements json.Marshaler, the extensions are added as members of the object.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5) //nolint:gomnd // 5 reserved members
+	for k, v := range p.Extensions {
+		if !isReservedMember(k) {
+			m[k] = v
+		}
+	}
+	m["type"] = p.Type
+	if m["type"] == "" {
+		m["type"] = DefaultType
+	}
+	if p.Title != "" {
+		m["title"] = p.Title
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, unknown members are added to the extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	var result Problem
+	targets := map[string]any{
+		"type":     &result.Type,
+		"title":    &result.Title,
+		"status":   &result.Status,
+		"detail":   &result.Detail,
+		"instance": &result.Instance,
+	}
+	for name, raw := range members {
+		if target, ok := targets[name]; ok {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return err
+			}
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		if result.Extensions == nil {
+			result.Extensions = make(map[string]any)
+		}
+		result.Extensions[name] = value
+	}
+	if result.Type == "" {
+		result.Type = DefaultType
+	}
+	*p = result
+	return nil
+}
@@ -0,0 +1,31 @@
+package problem_test
+
+import (
+	"reflect"
+	"runtime/debug"
+	"testing"
+)
+
+func Equal(t *testing.T, expected, actual any) {
+	if expected == nil && actual == nil {
+		return
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected %+v, but was %+v\n%s", expected, actual, string(debug.Stack()))
+	}
+}
+
+func Nil(t *testing.T, actual any) {
+	if actual == nil {
+		return
+	}
+	value := reflect.ValueOf(actual)
+	switch value.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		if value.IsNil() {
+			return
+		}
+	default:
+	}
+	t.Fatalf("expected %+v to be nil\n%s", actual, string(debug.Stack()))
+}
@@ -0,0 +1,183 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Eun/serrors"
+)
+
+// Writer writes errors as problem details.
+// The zero value is ready to use and responds with http.StatusInternalServerError.
+type Writer struct {
+	// Debug adds all fields and the stack (see serrors.GetStack) of the error to the problem.
+	// Sensitive field values are still redacted.
+	Debug bool
+	// DefaultStatus is the status code that is used if the error could not be mapped to a status code.
+	// If it is not set http.StatusInternalServerError will be used.
+	DefaultStatus int
+	// TypeBaseURI is used to build the type of the problem for errors that carry a code (see serrors.Code),
+	// the type will be TypeBaseURI followed by the code.
+	// If it is empty or the error does not carry a code the type will be DefaultType.
+	TypeBaseURI string
+	// PublicFields holds the keys of the fields (see serrors.GetFields) that are safe to be exposed,
+	// those fields are added as extension members.
+	PublicFields []string
+	// StatusField is the key of a field that holds the status code for the error.
+	StatusField string
+	// CodeStatus maps the codes of errors (see serrors.GetCode) to status codes.
+	CodeStatus map[serrors.Code]int
+	// TargetStatus maps errors to status codes using errors.Is, the first match wins.
+	TargetStatus []TargetStatus
+	// DetailFunc returns the detail of the problem for the error, it is not used in Debug mode.
+	// If it is not set the message of the outermost error is used for client errors (status < 500),
+	// so the texts of internal causes are not leaked.
+	DetailFunc func(err error) string
+}
+
+// TargetStatus maps all errors that match Target (using errors.Is) to Status.
+type TargetStatus struct {
+	Target error
+	Status int
+}
+
+// Status returns the status code for the error.
+// The status code is determined in the following order:
+//  1. the value of the StatusField
+//  2. the code of the error using CodeStatus
+//  3. the first match in TargetStatus
+//  4. DefaultStatus
+func (w *Writer) Status(err error) int {
+	if w.StatusField != "" {
+		if status, ok := toStatus(serrors.GetFields(err)[w.StatusField]); ok {
+			return status
+		}
+	}
+	if code := serrors.GetCode(err); code != "" {
+		if status, ok := w.CodeStatus[code]; ok {
+			return status
+		}
+	}
+	for _, target := range w.TargetStatus {
+		if errors.Is(err, target.Target) {
+			return target.Status
+		}
+	}
+	if w.DefaultStatus != 0 {
+		return w.DefaultStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// Problem converts the error into a Problem.
+// The detail of the problem is the message of the outermost error (see DetailFunc),
+// for server errors (status >= 500) it will only be added in Debug mode, so internal details are not leaked.
+// In Debug mode the detail is the full error text.
+func (w *Writer) Problem(err error) *Problem {
+	status := w.Status(err)
+	p := &Problem{
+		Type:       DefaultType,
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     "",
+		Instance:   "",
+		Extensions: nil,
+	}
+	if err == nil {
+		return p
+	}
+
+	switch {
+	case w.Debug:
+		p.Detail = err.Error()
+	case w.DetailFunc != nil:
+		p.Detail = w.DetailFunc(err)
+	case status < http.StatusInternalServerError:
+		p.Detail = serrors.GetStack(err)[0].ErrorMessage
+	}
+
+	code := serrors.GetCode(err)
+	if code != "" {
+		if w.TypeBaseURI != "" {
+			p.Type = w.TypeBaseURI + string(code)
+		}
+		p.addExtension("code", code)
+	}
+
+	fields := serrors.GetOrderedFields(err)
+	for _, field := range fields {
+		if w.Debug || w.isPublicField(field.Key) {
+			p.addExtension(field.Key, field.Value)
+		}
+	}
+
+	if w.Debug {
+		p.addExtension("stack", serrors.GetStack(err))
+	}
+	return p
+}
+
+// Write writes the error as problem details to the response.
+// If r is not nil, its path is used as the instance of the problem.
+func (w *Writer) Write(rw http.ResponseWriter, r *http.Request, err error) {
+	p := w.Problem(err)
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+
+	buf, marshalErr := json.Marshal(p)
+	if marshalErr != nil {
+		// fall back to a problem without extensions, they are the only part that could fail
+		p.Extensions = nil
+		buf, _ = json.Marshal(p)
+	}
+
+	rw.Header().Set("Content-Type", ContentType)
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.WriteHeader(p.Status)
+	_, _ = rw.Write(buf)
+}
+
+func (w *Writer) isPublicField(key string) bool {
+	for _, s := range w.PublicFields {
+		if s == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Problem) addExtension(key string, value any) {
+	if isReservedMember(key) {
+		return
+	}
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+}
+
+const (
+	minStatus = 100
+	maxStatus = 999
+)
+
+// toStatus converts a field value into a status code.
+func toStatus(v any) (int, bool) {
+	var status int
+	switch x := v.(type) {
+	case int:
+		status = x
+	case int64:
+		status = int(x)
+	case float64:
+		status = int(x)
+	default:
+		return 0, false
+	}
+	if status < minStatus || status > maxStatus {
+		return 0, false
+	}
+	return status, true
+}
@@ -0,0 +1,199 @@
+package problem_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eun/serrors"
+	"github.com/Eun/serrors/problem"
+)
+
+const (
+	codeNotFound serrors.Code = "not_found"
+	codeConflict serrors.Code = "conflict"
+)
+
+var errTestUnauthorized = errors.New("unauthorized")
+
+func doRequest(t *testing.T, w *problem.Writer, err error) (*http.Response, map[string]any) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w.Write(rw, r, err)
+	}))
+	defer server.Close()
+
+	req, reqErr := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/users/joe", http.NoBody)
+	Nil(t, reqErr)
+	resp, reqErr := server.Client().Do(req)
+	Nil(t, reqErr)
+	defer resp.Body.Close()
+
+	var body map[string]any
+	Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp, body
+}
+
+func TestWriter(t *testing.T) {
+	w := &problem.Writer{
+		TypeBaseURI:  "https://example.com/problems/",
+		PublicFields: []string{"user", "status"},
+		StatusField:  "http_status",
+		CodeStatus: map[serrors.Code]int{
+			codeNotFound: http.StatusNotFound,
+		},
+		TargetStatus: []problem.TargetStatus{
+			{Target: errTestUnauthorized, Status: http.StatusUnauthorized},
+		},
+	}
+
+	t.Run("code", func(t *testing.T) {
+		err := serrors.New("user not found").
+			WithCode(codeNotFound).
+			With("user", "joe").
+			With("internal", "value").
+			WithSecret("token", "secret")
+		resp, body := doRequest(t, w, err)
+
+		Equal(t, http.StatusNotFound, resp.StatusCode)
+		Equal(t, problem.ContentType, resp.Header.Get("Content-Type"))
+		Equal(t, map[string]any{
+			"type":     "https://example.com/problems/not_found",
+			"title":    "Not Found",
+			"status":   float64(http.StatusNotFound),
+			"detail":   "user not found",
+			"instance": "/users/joe",
+			"code":     "not_found",
+			"user":     "joe",
+		}, body)
+	})
+
+	t.Run("errors.Is target", func(t *testing.T) {
+		err := serrors.Wrap(errTestUnauthorized, "login failed")
+		resp, body := doRequest(t, w, err)
+
+		Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		Equal(t, map[string]any{
+			"type":     "about:blank",
+			"title":    "Unauthorized",
+			"status":   float64(http.StatusUnauthorized),
+			"detail":   "login failed",
+			"instance": "/users/joe",
+		}, body)
+	})
+
+	t.Run("client errors do not expose causes", func(t *testing.T) {
+		cause := fmt.Errorf("scan row: %w", errors.New("pq: relation users does not exist"))
+		err := serrors.Wrap(cause, "user not found").WithCode(codeNotFound)
+		resp, body := doRequest(t, w, err)
+
+		Equal(t, http.StatusNotFound, resp.StatusCode)
+		Equal(t, "user not found", body["detail"])
+	})
+
+	t.Run("detail func", func(t *testing.T) {
+		detailWriter := *w
+		detailWriter.DetailFunc = func(err error) string {
+			return "custom: " + serrors.GetStack(err)[0].ErrorMessage
+		}
+		err := serrors.Wrap(errors.New("internal"), "user not found").WithCode(codeNotFound)
+		_, body := doRequest(t, &detailWriter, err)
+		Equal(t, "custom: user not found", body["detail"])
+	})
+
+	t.Run("status field", func(t *testing.T) {
+		err := serrors.New("conflict").WithCode(codeNotFound).With("http_status", http.StatusConflict)
+		resp, _ := doRequest(t, w, err)
+		Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("reserved members are not overwritten", func(t *testing.T) {
+		err := serrors.New("some error").WithCode(codeNotFound).With("status", "overwritten")
+		resp, body := doRequest(t, w, err)
+		Equal(t, http.StatusNotFound, resp.StatusCode)
+		Equal(t, float64(http.StatusNotFound), body["status"])
+	})
+
+	t.Run("server errors do not expose details", func(t *testing.T) {
+		err := fmt.Errorf("query failed: %w", serrors.New("connection refused").WithCode(codeConflict))
+		resp, body := doRequest(t, w, err)
+
+		Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		Equal(t, map[string]any{
+			"type":     "https://example.com/problems/conflict",
+			"title":    "Internal Server Error",
+			"status":   float64(http.StatusInternalServerError),
+			"instance": "/users/joe",
+			"code":     "conflict",
+		}, body)
+	})
+
+	t.Run("default status", func(t *testing.T) {
+		resp, _ := doRequest(t, &problem.Writer{DefaultStatus: http.StatusBadRequest}, errors.New("some error"))
+		Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("debug", func(t *testing.T) {
+		debugWriter := *w
+		debugWriter.Debug = true
+
+		err := serrors.New("some error").With("internal", "value").WithSecret("token", "secret")
+		resp, body := doRequest(t, &debugWriter, err)
+
+		Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		Equal(t, "some error", body["detail"])
+		Equal(t, "value", body["internal"])
+		Equal(t, "[REDACTED]", body["token"])
+
+		stack, ok := body["stack"].([]any)
+		Equal(t, true, ok)
+		Equal(t, 1, len(stack))
+		Equal(t, "some error", stack[0].(map[string]any)["error_message"])
+	})
+}
+
+func TestWriter_Problem(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		p := (&problem.Writer{}).Problem(nil)
+		Equal(t, &problem.Problem{
+			Type:   problem.DefaultType,
+			Title:  "Internal Server Error",
+			Status: http.StatusInternalServerError,
+		}, p)
+	})
+
+	t.Run("without request", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		(&problem.Writer{}).Write(rec, nil, errors.New("some error"))
+		Equal(t, http.StatusInternalServerError, rec.Code)
+		Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+		Equal(t, `{"status":500,"title":"Internal Server Error","type":"about:blank"}`, rec.Body.String())
+	})
+}
+
+func TestProblem_JSON(t *testing.T) {
+	p := &problem.Problem{
+		Type:     "https://example.com/problems/not_found",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "user not found",
+		Instance: "/users/joe",
+		Extensions: map[string]any{
+			"user": "joe",
+		},
+	}
+	buf, err := json.Marshal(p)
+	Nil(t, err)
+
+	var decoded problem.Problem
+	Nil(t, json.Unmarshal(buf, &decoded))
+	Equal(t, *p, decoded)
+
+	Nil(t, json.Unmarshal([]byte(`{}`), &decoded))
+	Equal(t, problem.Problem{Type: problem.DefaultType}, decoded)
+	Equal(t, true, json.Unmarshal([]byte(`{"status":"abc"}`), &decoded) != nil)
+	Equal(t, true, json.Unmarshal([]byte(`[]`), &decoded) != nil)
+}
@@ -0,0 +1,83 @@
+package serrors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// PanicField is the key of the field that holds the recovered panic value.
+const PanicField = "panic"
+
+// Recoverer converts panics into errors, see Recover.
+type Recoverer struct {
+	// RepanicRuntimeErrors panics again if the panic value is a runtime.Error,
+	// e.g. a nil pointer dereference or an index out of range.
+	RepanicRuntimeErrors bool
+}
+
+// Recover converts a panic into an Error and stores it in err, it must be called directly with defer:
+//
+//	func run() (err error) {
+//		defer serrors.Recover(&err)
+//		...
+//	}
+//
+// The panic value is added as the field PanicField, if the panic value is an error it is the cause
+// of the returned error. The stack of the error is captured where the panic occurred.
+// If there was no panic, err is not modified.
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		Recoverer{}.handle(r, err)
+	}
+}
+
+// Recover converts a panic into an Error and stores it in err, it must be called directly with defer.
+// See the package level Recover for details.
+func (rec Recoverer) Recover(err *error) {
+	if r := recover(); r != nil {
+		rec.handle(r, err)
+	}
+}
+
+func (rec Recoverer) handle(value any, err *error) {
+	if _, ok := value.(runtime.Error); ok && rec.RepanicRuntimeErrors {
+		panic(value)
+	}
+	serr := newPanicError(currentStackOptions(), value)
+	if err != nil {
+		*err = serr
+	}
+}
+
+// newPanicError creates an Error for the panic value, the stack starts at the frame that panicked.
+func newPanicError(opts stackOptions, value any) *Error {
+	var err *Error
+	if opts, ok := opts.forMode(); ok {
+		var buf [stackBufferSize]uintptr
+		err = newErrorWithStack(trimToPanic(collectStack(opts, buf[:])), opts)
+	} else {
+		err = &Error{}
+	}
+
+	err.fields = []Field{{Key: PanicField, Value: value}}
+	if cause, ok := value.(error); ok {
+		err.message = "panic"
+		err.cause = cause
+		return err
+	}
+	err.message = fmt.Sprintf("panic: %v", value)
+	return err
+}
+
+// trimToPanic removes all program counters up to and including the one of the latest runtime.gopanic,
+// so the first program counter is the one that panicked.
+func trimToPanic(pcs []uintptr) []uintptr {
+	for i, pc := range pcs {
+		for _, frame := range stackCache.framesForPC(pc) {
+			if frame.Func == "runtime.gopanic" {
+				return pcs[i+1:]
+			}
+		}
+	}
+	return pcs
+}
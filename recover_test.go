@@ -0,0 +1,92 @@
+//go:build !serrors_without_stack
+// +build !serrors_without_stack
+
+package serrors_test
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func panicWithValue(value any) (err error) {
+	defer serrors.Recover(&err)
+	panic(value) // [panicWithValue00]
+}
+
+func panicWithNilPointer(rec serrors.Recoverer) (err error) {
+	defer rec.Recover(&err)
+	var m *struct{ value int }
+	_ = m.value // [panicWithNilPointer00]
+	return nil
+}
+
+func noPanic() (err error) {
+	defer serrors.Recover(&err)
+	return io.EOF
+}
+
+func TestRecover(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	t.Run("value", func(t *testing.T) {
+		err := panicWithValue("boom")
+		Equal(t, "panic: boom", err.Error())
+		Equal(t, map[string]any{serrors.PanicField: "boom"}, serrors.GetFields(err))
+		Equal(t, buildStackFrameFromMarker(t, filename, "panicWithValue00"), serrors.GetStack(err)[0].StackTrace[0])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := panicWithValue(io.EOF)
+		Equal(t, "panic: EOF", err.Error())
+		Equal(t, true, errors.Is(err, io.EOF))
+		Equal(t, map[string]any{serrors.PanicField: io.EOF}, serrors.GetFields(err))
+		Equal(t, buildStackFrameFromMarker(t, filename, "panicWithValue00"), serrors.GetStack(err)[0].StackTrace[0])
+	})
+
+	t.Run("runtime error", func(t *testing.T) {
+		err := panicWithNilPointer(serrors.Recoverer{})
+		var runtimeError runtime.Error
+		Equal(t, true, errors.As(err, &runtimeError))
+		Equal(t, buildStackFrameFromMarker(t, filename, "panicWithNilPointer00"), serrors.GetStack(err)[0].StackTrace[0])
+	})
+
+	t.Run("repanic runtime error", func(t *testing.T) {
+		var recovered any
+		func() {
+			defer func() {
+				recovered = recover()
+			}()
+			_ = panicWithNilPointer(serrors.Recoverer{RepanicRuntimeErrors: true})
+		}()
+		_, ok := recovered.(runtime.Error)
+		Equal(t, true, ok)
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		Equal(t, io.EOF, noPanic())
+	})
+
+	t.Run("filter at capture", func(t *testing.T) {
+		serrors.SetFrameFilterMode(serrors.FilterAtCapture)
+		defer serrors.SetFrameFilterMode(serrors.FilterAtRender)
+
+		serrors.SetFrameFilters()
+		err := panicWithValue("boom")
+		serrors.SetFrameFilters(serrors.DefaultFrameFilters()...)
+		Equal(t, true, hasFrame(err, "testing."))
+	})
+
+	t.Run("stack mode off", func(t *testing.T) {
+		serrors.SetStackMode(serrors.StackOff)
+		defer serrors.SetStackMode(serrors.StackFull)
+
+		err := panicWithValue("boom")
+		Equal(t, "panic: boom", err.Error())
+		Nil(t, serrors.GetStack(err)[0].StackTrace)
+	})
+}
@@ -0,0 +1,127 @@
+package serrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+// RedactedValue is the text that is used by the default Redactor.
+const RedactedValue = "[REDACTED]"
+
+// Redactor returns the text that is shown instead of a sensitive value.
+type Redactor func(value any) string
+
+var redactor atomic.Pointer[Redactor]
+
+// SetRedactor sets the Redactor that is used for all sensitive values.
+// Passing nil restores the default Redactor, which replaces every value with RedactedValue.
+func SetRedactor(r Redactor) {
+	if r == nil {
+		redactor.Store(nil)
+		return
+	}
+	redactor.Store(&r)
+}
+
+func redact(value any) string {
+	if r := redactor.Load(); r != nil {
+		return (*r)(value)
+	}
+	return RedactedValue
+}
+
+// SensitiveValue holds a value that must not be revealed, e.g. an email address, a token or sql arguments.
+// It is redacted in all outputs: fmt (including the %+v output of Error), encoding/json,
+// yaml (using MarshalYAML), encoding.TextMarshaler and slog.
+// The redaction can be configured using SetRedactor.
+// To reveal the value in trusted sinks use SensitiveValue.Reveal, Reveal, RevealFields or
+// LogOptions.RevealSensitive.
+type SensitiveValue struct {
+	value any
+}
+
+// Sensitive marks the value as sensitive.
+func Sensitive(value any) SensitiveValue {
+	return SensitiveValue{value: value}
+}
+
+// Reveal returns the underlying value.
+func (s SensitiveValue) Reveal() any {
+	return s.value
+}
+
+// String returns the redacted value.
+func (s SensitiveValue) String() string {
+	return redact(s.value)
+}
+
+// GoString returns the redacted value.
+func (s SensitiveValue) GoString() string {
+	return s.String()
+}
+
+// Format formats the redacted value, all verbs are supported.
+func (s SensitiveValue) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = fmt.Fprintf(f, "%q", s.String())
+		return
+	}
+	_, _ = io.WriteString(f, s.String())
+}
+
+// MarshalJSON implements json.Marshaler, the redacted value is encoded as a string.
+func (s SensitiveValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// MarshalText implements encoding.TextMarshaler, the redacted value is returned.
+func (s SensitiveValue) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// MarshalYAML returns the redacted value for yaml encoders.
+func (s SensitiveValue) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
+
+// LogValue implements slog.LogValuer, the redacted value is returned.
+func (s SensitiveValue) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// WithSecret returns a copy of the error with the field key set to the sensitive value.
+// It is a shortcut for With(key, Sensitive(value)).
+func (e *Error) WithSecret(key string, value any) *Error {
+	return e.With(key, Sensitive(value))
+}
+
+// WithSecret returns a copy of the ErrorBuilder with the field key set to the sensitive value.
+// It is a shortcut for With(key, Sensitive(value)).
+func (eb *ErrorBuilder) WithSecret(key string, value any) *ErrorBuilder {
+	return eb.With(key, Sensitive(value))
+}
+
+// Reveal returns the underlying value if v is a SensitiveValue, otherwise v is returned.
+// Only use it for trusted sinks.
+func Reveal(v any) any {
+	if s, ok := v.(SensitiveValue); ok {
+		return s.value
+	}
+	return v
+}
+
+// RevealFields returns a copy of fields with all sensitive values revealed.
+// Only use it for trusted sinks.
+func RevealFields(fields map[string]any) map[string]any {
+	if fields == nil {
+		return nil
+	}
+	result := make(map[string]any, len(fields))
+	for k, v := range fields {
+		result[k] = Reveal(v)
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package serrors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+var _ slog.LogValuer = serrors.Sensitive(nil) // make sure sensitive values are redacted in slog
+
+func TestSensitive(t *testing.T) {
+	err := serrors.Wrap(
+		serrors.New("error 1").WithSecret("token", "secret-token"),
+		"error 2",
+	).With("email", serrors.Sensitive("joe@example.com")).With("user", "joe")
+
+	t.Run("fmt", func(t *testing.T) {
+		Equal(t, "error 2: error 1[email=[REDACTED] token=[REDACTED] user=joe]", fmt.Sprintf("%v", err))
+
+		verbose := fmt.Sprintf("%+v", err)
+		Equal(t, false, strings.Contains(verbose, "secret-token"))
+		Equal(t, false, strings.Contains(verbose, "joe@example.com"))
+		Equal(t, true, strings.Contains(verbose, "[email=[REDACTED] user=joe]"))
+		Equal(t, true, strings.Contains(verbose, "[token=[REDACTED]]"))
+
+		value := serrors.Sensitive("secret")
+		Equal(t, "[REDACTED]", fmt.Sprintf("%s", value))
+		Equal(t, "[REDACTED]", fmt.Sprintf("%+v", value))
+		Equal(t, "[REDACTED]", fmt.Sprintf("%#v", value))
+		Equal(t, `"[REDACTED]"`, fmt.Sprintf("%q", value))
+		Equal(t, "[REDACTED]", value.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf, jsonErr := json.Marshal(serrors.GetStack(err))
+		Nil(t, jsonErr)
+		Equal(t, false, strings.Contains(string(buf), "secret-token"))
+		Equal(t, false, strings.Contains(string(buf), "joe@example.com"))
+		Equal(t, true, strings.Contains(string(buf), `"token":"[REDACTED]"`))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		v, yamlErr := serrors.Sensitive("secret").MarshalYAML()
+		Nil(t, yamlErr)
+		Equal(t, "[REDACTED]", v)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		v, textErr := serrors.Sensitive("secret").MarshalText()
+		Nil(t, textErr)
+		Equal(t, "[REDACTED]", string(v))
+	})
+
+	t.Run("slog", func(t *testing.T) {
+		defer serrors.SetLogOptions(serrors.LogOptions{})
+		serrors.SetLogOptions(serrors.LogOptions{})
+
+		line := logAndDecode(t, "error", err, "password", serrors.Sensitive("secret"))
+		Equal(t, "[REDACTED]", line["password"])
+		Equal(t, map[string]any{
+			"email": "[REDACTED]",
+			"user":  "joe",
+			"token": "[REDACTED]",
+		}, line["error"].(map[string]any)["fields"])
+		buf, jsonErr := json.Marshal(line)
+		Nil(t, jsonErr)
+		Equal(t, false, strings.Contains(string(buf), "secret-token"))
+	})
+
+	t.Run("slog reveal", func(t *testing.T) {
+		defer serrors.SetLogOptions(serrors.LogOptions{})
+		serrors.SetLogOptions(serrors.LogOptions{RevealSensitive: true})
+
+		line := logAndDecode(t, "error", err)
+		Equal(t, map[string]any{
+			"email": "joe@example.com",
+			"user":  "joe",
+			"token": "secret-token",
+		}, line["error"].(map[string]any)["fields"])
+		buf, jsonErr := json.Marshal(line)
+		Nil(t, jsonErr)
+		Equal(t, false, strings.Contains(string(buf), "[REDACTED]"))
+	})
+
+	t.Run("slog reveal per layer", func(t *testing.T) {
+		defer serrors.SetLogOptions(serrors.LogOptions{})
+		serrors.SetLogOptions(serrors.LogOptions{RevealSensitive: true, Fields: serrors.LogFieldsPerLayer})
+
+		line := logAndDecode(t, "error", err)
+		buf, jsonErr := json.Marshal(line)
+		Nil(t, jsonErr)
+		Equal(t, true, strings.Contains(string(buf), "secret-token"))
+		Equal(t, false, strings.Contains(string(buf), "[REDACTED]"))
+	})
+
+	t.Run("reveal", func(t *testing.T) {
+		Equal(t, "secret", serrors.Sensitive("secret").Reveal())
+		Equal(t, "secret", serrors.Reveal(serrors.Sensitive("secret")))
+		Equal(t, "plain", serrors.Reveal("plain"))
+		Equal(t, map[string]any{
+			"email": "joe@example.com",
+			"user":  "joe",
+			"token": "secret-token",
+		}, serrors.RevealFields(serrors.GetFields(err)))
+		Nil(t, serrors.RevealFields(nil))
+	})
+
+	t.Run("custom redactor", func(t *testing.T) {
+		defer serrors.SetRedactor(nil)
+		serrors.SetRedactor(func(value any) string {
+			s := fmt.Sprint(value)
+			return "***" + s[len(s)-4:]
+		})
+		Equal(t, "***oken", serrors.Sensitive("secret-token").String())
+		Equal(t, "error 2: error 1[email=***.com token=***oken user=joe]", fmt.Sprintf("%v", err))
+
+		serrors.SetRedactor(nil)
+		Equal(t, "[REDACTED]", serrors.Sensitive("secret-token").String())
+	})
+
+	t.Run("builder", func(t *testing.T) {
+		builderErr := serrors.NewBuilder().WithSecret("token", "secret-token").New("some error")
+		Equal(t, serrors.Sensitive("secret-token"), serrors.GetFields(builderErr)["token"])
+		Equal(t, "some error[token=[REDACTED]]", fmt.Sprintf("%v", builderErr))
+	})
+}
@@ -0,0 +1,59 @@
+package serrors
+
+// SentinelError is a template for errors that should be comparable with errors.Is.
+// Instead of returning the sentinel itself, SentinelError.New or SentinelError.Wrap
+// should be used to create a new Error for every occurrence, so that every error
+// has its own stack and its own fields:
+//
+//	var ErrUserNotFound = serrors.Sentinel("user not found")
+//
+//	func findUser(id string) error {
+//		return ErrUserNotFound.New().With("id", id)
+//	}
+//
+// errors.Is(err, ErrUserNotFound) reports true for every error that was created from the sentinel.
+type SentinelError struct {
+	message string
+	code    Code
+}
+
+// Sentinel creates a new SentinelError with the supplied message.
+func Sentinel(message string) *SentinelError {
+	return &SentinelError{
+		message: message,
+		code:    "",
+	}
+}
+
+// Error returns the message of the sentinel.
+func (s *SentinelError) Error() string {
+	return s.message
+}
+
+// WithCode returns a copy of the sentinel with the code set.
+// All errors that are created by the returned sentinel will carry the code.
+// Note that the returned sentinel is a different sentinel, so it should only be used
+// when declaring the sentinel.
+func (s *SentinelError) WithCode(code Code) *SentinelError {
+	return &SentinelError{
+		message: s.message,
+		code:    code,
+	}
+}
+
+// New creates a new Error with the message of the sentinel.
+func (s *SentinelError) New() *Error {
+	err := New(s.message)
+	err.code = s.code
+	err.sentinel = s
+	return err
+}
+
+// Wrap creates a new Error with the message of the sentinel.
+// The passed in error will be added as a cause for this error.
+func (s *SentinelError) Wrap(err error) *Error {
+	serr := Wrap(err, s.message)
+	serr.code = s.code
+	serr.sentinel = s
+	return serr
+}
@@ -0,0 +1,77 @@
+package serrors_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+var (
+	errTestSentinel      = serrors.Sentinel("user not found")
+	errTestOtherSentinel = serrors.Sentinel("user not found")
+	errTestCodeSentinel  = serrors.Sentinel("user not found").WithCode(codeNotFound)
+)
+
+func newSentinelError(id int) error {
+	return errTestSentinel.New().With("id", id) // [TestSentinel00]
+}
+
+func TestSentinel(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	t.Run("New", func(t *testing.T) {
+		err1 := newSentinelError(1)
+		err2 := newSentinelError(2)
+
+		Equal(t, "user not found", err1.Error())
+		Equal(t, true, errors.Is(err1, errTestSentinel))
+		Equal(t, true, errors.Is(err2, errTestSentinel))
+		Equal(t, true, errors.Is(fmt.Errorf("wrapped: %w", err1), errTestSentinel))
+		Equal(t, false, errors.Is(err1, errTestOtherSentinel))
+		Equal(t, false, errors.Is(err1, err2))
+
+		Equal(t, map[string]any{"id": 1}, serrors.GetFields(err1))
+		Equal(t, map[string]any{"id": 2}, serrors.GetFields(err2))
+
+		expectedStack := []serrors.ErrorStack{
+			{
+				ErrorMessage: "user not found",
+				Fields:       map[string]any{"id": 1},
+				StackTrace: []serrors.StackFrame{
+					buildStackFrameFromMarker(t, filename, "TestSentinel00"),
+					buildStackFrameFromMarker(t, filename, "TestSentinel01"),
+				},
+			},
+		}
+		err := newSentinelError(1) // [TestSentinel01]
+		skipWithoutStack(t)
+		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		cause := errors.New("cause")
+		err := errTestSentinel.Wrap(cause)
+		Equal(t, "user not found: cause", err.Error())
+		Equal(t, true, errors.Is(err, errTestSentinel))
+		Equal(t, true, errors.Is(err, cause))
+	})
+
+	t.Run("WithCode", func(t *testing.T) {
+		err := errTestCodeSentinel.New()
+		Equal(t, true, errors.Is(err, errTestCodeSentinel))
+		Equal(t, true, errors.Is(err, codeNotFound))
+		Equal(t, false, errors.Is(err, errTestSentinel))
+		Equal(t, codeNotFound, serrors.GetCode(errTestCodeSentinel.Wrap(errors.New("cause"))))
+	})
+
+	t.Run("sentinel is not modified", func(t *testing.T) {
+		_ = errTestSentinel.New().With("id", 1)
+		Equal(t, "user not found", errTestSentinel.Error())
+		Nil(t, serrors.GetFields(errTestSentinel))
+		Nil(t, serrors.GetFields(errTestSentinel.New()))
+	})
+}
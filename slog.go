@@ -0,0 +1,152 @@
+package serrors
+
+import (
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+)
+
+// LogFieldsMode defines how the fields of an error are rendered by LogValue.
+type LogFieldsMode int
+
+const (
+	// LogFieldsFlat renders the merged fields of the whole error chain (see GetFields)
+	// into a single "fields" group.
+	LogFieldsFlat LogFieldsMode = iota
+	// LogFieldsPerLayer renders a "chain" group that contains one group for every error
+	// in the chain, each group holds the message and the fields of that specific error.
+	// Errors with multiple causes contain a "causes" group with one chain for every cause.
+	LogFieldsPerLayer
+)
+
+// LogOptions configures the slog.Value that is produced for an error.
+// The zero value renders flat fields and includes the stack.
+type LogOptions struct {
+	// Fields defines how the fields should be rendered.
+	Fields LogFieldsMode
+	// OmitStack removes the stack from the output.
+	OmitStack bool
+	// RevealSensitive reveals the values of sensitive fields (see Sensitive).
+	// Only enable it for trusted sinks.
+	RevealSensitive bool
+}
+
+var logOptions atomic.Pointer[LogOptions]
+
+// SetLogOptions sets the options that are used when an Error is logged using slog.
+func SetLogOptions(opts LogOptions) {
+	logOptions.Store(&opts)
+}
+
+func currentLogOptions() LogOptions {
+	if opts := logOptions.Load(); opts != nil {
+		return *opts
+	}
+	return LogOptions{}
+}
+
+// LogValue implements slog.LogValuer.
+// The error will be rendered as a group with its message, its fields and its stack,
+// the output can be configured with SetLogOptions.
+func (e *Error) LogValue() slog.Value {
+	if e == nil {
+		return slog.StringValue("<nil>")
+	}
+	return LogValue(e, currentLogOptions())
+}
+
+// LogValue returns the slog.Value for the specified error using the provided options.
+// It can be used for any error, not only for Error.
+func LogValue(err error, opts LogOptions) slog.Value {
+	if err == nil {
+		return slog.AnyValue(nil)
+	}
+	if e, ok := err.(*Error); ok && e == nil {
+		return slog.StringValue("<nil>")
+	}
+
+	attrs := []slog.Attr{slog.String("message", err.Error())}
+	if code := GetCode(err); code != "" {
+		attrs = append(attrs, slog.String("code", string(code)))
+	}
+
+	if opts.Fields == LogFieldsPerLayer {
+		attrs = append(attrs, slog.Attr{Key: "chain", Value: logValueForChain(GetStack(err), opts)})
+		return slog.GroupValue(attrs...)
+	}
+
+	if fields := GetOrderedFields(err); len(fields) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fieldsToAttrs(fields, opts)...)})
+	}
+	if !opts.OmitStack {
+		if stack := GetStack(err); len(stack) > 0 {
+			attrs = append(attrs, slog.Attr{Key: "stack", Value: logValueForChain(stack, opts)})
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func logValueForChain(stack []ErrorStack, opts LogOptions) slog.Value {
+	layers := make([]slog.Attr, len(stack))
+	for i := range stack {
+		layers[i] = slog.Attr{
+			Key:   strconv.Itoa(i),
+			Value: logValueForLayer(&stack[i], opts),
+		}
+	}
+	return slog.GroupValue(layers...)
+}
+
+func logValueForLayer(errorStack *ErrorStack, opts LogOptions) slog.Value {
+	attrs := []slog.Attr{slog.String("message", errorStack.ErrorMessage)}
+	if errorStack.Code != "" {
+		attrs = append(attrs, slog.String("code", string(errorStack.Code)))
+	}
+	if len(errorStack.Fields) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fieldsToAttrs(errorStack.orderedFields(), opts)...)})
+	}
+	if !opts.OmitStack && len(errorStack.StackTrace) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "stack_trace", Value: logValueForFrames(errorStack.StackTrace)})
+	}
+	if len(errorStack.Causes) > 0 {
+		causes := make([]slog.Attr, len(errorStack.Causes))
+		for i, cause := range errorStack.Causes {
+			causes[i] = slog.Attr{Key: strconv.Itoa(i), Value: logValueForChain(cause, opts)}
+		}
+		attrs = append(attrs, slog.Attr{Key: "causes", Value: slog.GroupValue(causes...)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// logValueForFrames renders every frame as a group with its func, file and line,
+// the groups are keyed by the index of the frame.
+func logValueForFrames(frames []StackFrame) slog.Value {
+	attrs := make([]slog.Attr, len(frames))
+	for i, frame := range frames {
+		frameAttrs := []slog.Attr{
+			slog.String("func", frame.Func),
+			slog.String("file", frame.File),
+			slog.Int("line", frame.Line),
+		}
+		if frame.Remote {
+			frameAttrs = append(frameAttrs, slog.Bool("remote", true))
+		}
+		attrs[i] = slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(frameAttrs...)}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func fieldsToAttrs(fields []Field, opts LogOptions) []slog.Attr {
+	attrs := make([]slog.Attr, len(fields))
+	for i, field := range fields {
+		attrs[i] = fieldToAttr(field.Key, field.Value, opts)
+	}
+	return attrs
+}
+
+func fieldToAttr(key string, value any, opts LogOptions) slog.Attr {
+	if opts.RevealSensitive {
+		value = Reveal(value)
+	}
+	return slog.Any(key, value)
+}
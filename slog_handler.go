@@ -0,0 +1,106 @@
+package serrors
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+)
+
+// SlogHandler is a slog.Handler that expands errors found in the attributes of a record.
+// Every attribute that holds an error which carries fields or a stack (see GetFields and GetStack)
+// is replaced with the value that LogValue returns for this error before the record is passed to
+// the next handler.
+// This also works for errors that are wrapped by third party code, e.g. using fmt.Errorf.
+type SlogHandler struct {
+	next slog.Handler
+	opts LogOptions
+}
+
+// NewSlogHandler creates a new SlogHandler that expands errors using the provided options
+// and passes the records to next.
+func NewSlogHandler(next slog.Handler, opts LogOptions) *SlogHandler {
+	return &SlogHandler{
+		next: next,
+		opts: opts,
+	}
+}
+
+// Enabled reports whether the next handler handles records at the given level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands the errors in the attributes of the record and passes it to the next handler.
+//
+//nolint:gocritic // the record is passed by value to satisfy the slog.Handler interface
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	expanded := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(h.expandAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+// WithAttrs returns a new SlogHandler whose attributes consists of attrs with expanded errors.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		expanded[i] = h.expandAttr(attr)
+	}
+	return &SlogHandler{
+		next: h.next.WithAttrs(expanded),
+		opts: h.opts,
+	}
+}
+
+// WithGroup returns a new SlogHandler with the given group appended to the next handler's groups.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{
+		next: h.next.WithGroup(name),
+		opts: h.opts,
+	}
+}
+
+func (h *SlogHandler) expandAttr(attr slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, a := range group {
+			expanded[i] = h.expandAttr(a)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny, slog.KindLogValuer:
+		err, ok := attr.Value.Any().(error)
+		if !ok || isNilError(err) || !hasDetails(err) {
+			return attr
+		}
+		return slog.Attr{Key: attr.Key, Value: LogValue(err, h.opts)}
+	case slog.KindBool, slog.KindDuration, slog.KindFloat64, slog.KindInt64,
+		slog.KindString, slog.KindTime, slog.KindUint64:
+		return attr
+	}
+	return attr
+}
+
+// isNilError reports whether the error is a nil pointer, e.g. a typed nil *Error.
+// Such errors are passed to the next handler unchanged.
+func isNilError(err error) bool {
+	v := reflect.ValueOf(err)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// hasDetails reports whether the error carries fields or a stack somewhere in its chain.
+func hasDetails(err error) bool {
+	if len(GetFields(err)) > 0 {
+		return true
+	}
+	stack := GetStack(err)
+	for i := range stack {
+		if len(stack[i].StackTrace) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package serrors_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+var _ slog.Handler = &serrors.SlogHandler{} // make sure we implement the slog.Handler interface
+
+func newTestSlogHandlerLogger(buf *bytes.Buffer, opts serrors.LogOptions) *slog.Logger {
+	return slog.New(serrors.NewSlogHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}), opts))
+}
+
+func decodeLogLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	var result map[string]any
+	Nil(t, json.Unmarshal(buf.Bytes(), &result))
+	buf.Reset()
+	return result
+}
+
+func TestSlogHandler(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	serr := serrors.New("error 1").With("k1", "v1") // [TestSlogHandler00]
+	wrapped := fmt.Errorf("error 2: %w", serr)
+
+	expectedWrapped := map[string]any{
+		"message": "error 2: error 1[k1=v1]",
+		"fields":  map[string]any{"k1": "v1"},
+	}
+
+	t.Run("wrapped error", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{OmitStack: true})
+		logger.Error("log", "error", wrapped)
+		Equal(t, expectedWrapped, decodeLogLine(t, &buf)["error"])
+	})
+
+	t.Run("error with stack", func(t *testing.T) {
+		skipWithoutStack(t)
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{})
+		logger.Error("log", "error", wrapped)
+
+		expected := map[string]any{
+			"message": "error 2: error 1[k1=v1]",
+			"fields":  map[string]any{"k1": "v1"},
+			"stack": map[string]any{
+				"0": map[string]any{
+					"message": "error 2: error 1[k1=v1]",
+				},
+				"1": map[string]any{
+					"message": "error 1",
+					"fields":  map[string]any{"k1": "v1"},
+					"stack_trace": map[string]any{
+						"0": frameToJSONValue(buildStackFrameFromMarker(t, filename, "TestSlogHandler00")),
+					},
+				},
+			},
+		}
+		Equal(t, expected, decodeLogLine(t, &buf)["error"])
+	})
+
+	t.Run("plain error is not modified", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{OmitStack: true})
+		logger.Error("log", "error", errors.New("some error"))
+		Equal(t, "some error", decodeLogLine(t, &buf)["error"])
+	})
+
+	t.Run("typed nil error is not modified", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{})
+		var err *serrors.Error
+		logger.Error("log", "error", err)
+		Equal(t, "<nil>", decodeLogLine(t, &buf)["error"])
+	})
+
+	t.Run("non error attributes are not modified", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{OmitStack: true})
+		logger.Error("log", "key", "value", "number", 1)
+		line := decodeLogLine(t, &buf)
+		Equal(t, "value", line["key"])
+		Equal(t, float64(1), line["number"])
+	})
+
+	t.Run("error in group", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{OmitStack: true})
+		logger.Error("log", slog.Group("details", "error", wrapped))
+		Equal(t, map[string]any{"error": expectedWrapped}, decodeLogLine(t, &buf)["details"])
+	})
+
+	t.Run("error in WithAttrs", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{OmitStack: true}).With("error", wrapped)
+		logger.Error("log")
+		Equal(t, expectedWrapped, decodeLogLine(t, &buf)["error"])
+	})
+
+	t.Run("error in WithGroup", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{OmitStack: true}).WithGroup("details")
+		logger.Error("log", "error", wrapped)
+		Equal(t, map[string]any{"error": expectedWrapped}, decodeLogLine(t, &buf)["details"])
+	})
+
+	t.Run("handler options are used for Error", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newTestSlogHandlerLogger(&buf, serrors.LogOptions{OmitStack: true})
+		logger.Error("log", "error", serr)
+		Equal(t, map[string]any{
+			"message": "error 1",
+			"fields":  map[string]any{"k1": "v1"},
+		}, decodeLogLine(t, &buf)["error"])
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		handler := serrors.NewSlogHandler(slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}),
+			serrors.LogOptions{})
+		Equal(t, false, handler.Enabled(context.Background(), slog.LevelInfo))
+		Equal(t, true, handler.Enabled(context.Background(), slog.LevelError))
+	})
+}
@@ -0,0 +1,215 @@
+package serrors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+var _ slog.LogValuer = &serrors.Error{} // make sure we implement the slog.LogValuer interface
+
+func logAndDecode(t *testing.T, args ...any) map[string]any {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Error("log", args...)
+
+	var result map[string]any
+	Nil(t, json.Unmarshal(buf.Bytes(), &result))
+	return result
+}
+
+func toJSONValue(t *testing.T, v any) any {
+	buf, err := json.Marshal(v)
+	Nil(t, err)
+	var result any
+	Nil(t, json.Unmarshal(buf, &result))
+	return result
+}
+
+func TestError_LogValue(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	cause := serrors.New("error 1").With("k1", "v1").With("k", "inner")       // [TestError_LogValue01]
+	err := serrors.Wrap(cause, "error 2").With("k2", "v2").With("k", "outer") // [TestError_LogValue00]
+
+	t.Run("default", func(t *testing.T) {
+		skipWithoutStack(t)
+		defer serrors.SetLogOptions(serrors.LogOptions{})
+		serrors.SetLogOptions(serrors.LogOptions{})
+
+		expected := map[string]any{
+			"message": "error 2: error 1",
+			"fields": map[string]any{
+				"k":  "outer",
+				"k1": "v1",
+				"k2": "v2",
+			},
+			"stack": map[string]any{
+				"0": map[string]any{
+					"message": "error 2",
+					"fields":  map[string]any{"k2": "v2", "k": "outer"},
+					"stack_trace": map[string]any{
+						"0": frameToJSONValue(buildStackFrameFromMarker(t, filename, "TestError_LogValue00")),
+					},
+				},
+				"1": map[string]any{
+					"message": "error 1",
+					"fields":  map[string]any{"k1": "v1", "k": "inner"},
+					"stack_trace": map[string]any{
+						"0": frameToJSONValue(buildStackFrameFromMarker(t, filename, "TestError_LogValue01")),
+					},
+				},
+			},
+		}
+		Equal(t, expected, logAndDecode(t, "error", err)["error"])
+	})
+
+	t.Run("omit stack", func(t *testing.T) {
+		defer serrors.SetLogOptions(serrors.LogOptions{})
+		serrors.SetLogOptions(serrors.LogOptions{OmitStack: true})
+
+		expected := map[string]any{
+			"message": "error 2: error 1",
+			"fields": map[string]any{
+				"k":  "outer",
+				"k1": "v1",
+				"k2": "v2",
+			},
+		}
+		Equal(t, expected, logAndDecode(t, "error", err)["error"])
+	})
+
+	t.Run("per layer", func(t *testing.T) {
+		skipWithoutStack(t)
+		defer serrors.SetLogOptions(serrors.LogOptions{})
+		serrors.SetLogOptions(serrors.LogOptions{Fields: serrors.LogFieldsPerLayer})
+
+		expected := map[string]any{
+			"message": "error 2: error 1",
+			"chain": map[string]any{
+				"0": map[string]any{
+					"message": "error 2",
+					"fields":  map[string]any{"k2": "v2", "k": "outer"},
+					"stack_trace": map[string]any{
+						"0": frameToJSONValue(buildStackFrameFromMarker(t, filename, "TestError_LogValue00")),
+					},
+				},
+				"1": map[string]any{
+					"message": "error 1",
+					"fields":  map[string]any{"k1": "v1", "k": "inner"},
+					"stack_trace": map[string]any{
+						"0": frameToJSONValue(buildStackFrameFromMarker(t, filename, "TestError_LogValue01")),
+					},
+				},
+			},
+		}
+		Equal(t, expected, logAndDecode(t, "error", err)["error"])
+	})
+
+	t.Run("per layer without stack", func(t *testing.T) {
+		defer serrors.SetLogOptions(serrors.LogOptions{})
+		serrors.SetLogOptions(serrors.LogOptions{Fields: serrors.LogFieldsPerLayer, OmitStack: true})
+
+		expected := map[string]any{
+			"message": "error 2: error 1",
+			"chain": map[string]any{
+				"0": map[string]any{
+					"message": "error 2",
+					"fields":  map[string]any{"k2": "v2", "k": "outer"},
+				},
+				"1": map[string]any{
+					"message": "error 1",
+					"fields":  map[string]any{"k1": "v1", "k": "inner"},
+				},
+			},
+		}
+		Equal(t, expected, logAndDecode(t, "error", err)["error"])
+	})
+}
+
+func TestLogValue(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		Equal(t, slog.KindAny, serrors.LogValue(nil, serrors.LogOptions{}).Kind())
+		Nil(t, serrors.LogValue(nil, serrors.LogOptions{}).Any())
+	})
+	t.Run("typed nil error", func(t *testing.T) {
+		var err *serrors.Error
+		Equal(t, "<nil>", err.LogValue().String())
+		Equal(t, "<nil>", serrors.LogValue(err, serrors.LogOptions{}).String())
+	})
+	t.Run("third party error", func(t *testing.T) {
+		expected := map[string]any{
+			"message": "some error",
+			"stack": map[string]any{
+				"0": map[string]any{
+					"message": "some error",
+				},
+			},
+		}
+		Equal(t, expected, logAndDecode(t, "error", serrors.LogValue(errString("some error"), serrors.LogOptions{}))["error"])
+	})
+}
+
+func TestLogValue_TextHandler(t *testing.T) {
+	skipWithoutStack(t)
+
+	err := serrors.New("some error").With("key", "value")
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Error("log", "error", serrors.LogValue(err, serrors.LogOptions{}))
+
+	output := buf.String()
+	for _, s := range []string{
+		`error.stack.0.message="some error"`,
+		`error.stack.0.fields.key=value`,
+		`error.stack.0.stack_trace.0.func=github.com/Eun/serrors_test.TestLogValue_TextHandler`,
+		`error.stack.0.stack_trace.0.line=`,
+	} {
+		if !strings.Contains(output, s) {
+			t.Fatalf("expected %q to contain %q", output, s)
+		}
+	}
+}
+
+func TestLogValue_FieldOrder(t *testing.T) {
+	err := serrors.New("some error").With("b", "1").With("a", "2")
+
+	for _, mode := range []serrors.LogFieldsMode{serrors.LogFieldsFlat, serrors.LogFieldsPerLayer} {
+		var buf bytes.Buffer
+		opts := serrors.LogOptions{Fields: mode, OmitStack: true}
+		slog.New(slog.NewTextHandler(&buf, nil)).Error("log", "error", serrors.LogValue(err, opts))
+
+		output := buf.String()
+		b := strings.Index(output, "fields.b=1")
+		a := strings.Index(output, "fields.a=2")
+		if b < 0 || a < 0 || b > a {
+			t.Fatalf("expected the fields to be in the order they were added: %s", output)
+		}
+	}
+}
+
+// frameToJSONValue returns the json representation of a frame as it is rendered by LogValue.
+func frameToJSONValue(frame serrors.StackFrame) map[string]any {
+	return map[string]any{
+		"func": frame.Func,
+		"file": frame.File,
+		"line": float64(frame.Line),
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
@@ -1,7 +1,7 @@
 package serrors
 
 import (
-	"errors"
+	"sort"
 	"strings"
 )
 
@@ -10,14 +10,27 @@ type StackFrame struct {
 	File string `json:"file" yaml:"file"`
 	Func string `json:"func" yaml:"func"`
 	Line int    `json:"line" yaml:"line"`
+	// Remote is set for frames that were not captured in this process,
+	// e.g. for errors that were decoded from json.
+	Remote bool `json:"remote,omitempty" yaml:"remote,omitempty"`
 }
 
 // ErrorStack holds an error and its relevant information.
 type ErrorStack struct {
-	error        error
+	error error
+	// fields holds the fields in the order they were added, Fields holds the same fields as a map.
+	fields       []Field
 	ErrorMessage string         `json:"error_message" yaml:"error_message"`
+	Code         Code           `json:"code,omitempty" yaml:"code,omitempty"`
 	Fields       map[string]any `json:"fields" yaml:"fields"`
 	StackTrace   []StackFrame   `json:"stack_trace" yaml:"stack_trace"`
+	// FramesInCommon is the number of frames that were removed from the end of StackTrace,
+	// because they are the same as in the stack trace of the cause (see ElideCommonFrames).
+	FramesInCommon int `json:"frames_in_common,omitempty" yaml:"frames_in_common,omitempty"`
+	// Causes is only set when the error has multiple causes (e.g. errors.Join).
+	// Each element is the stack of one cause, as GetStack would return it for that cause.
+	// The texts of the causes are removed from ErrorMessage, so they are not repeated.
+	Causes [][]ErrorStack `json:"causes,omitempty" yaml:"causes,omitempty"`
 }
 
 // Error returns the main error.
@@ -25,6 +38,42 @@ func (es *ErrorStack) Error() error {
 	return es.error
 }
 
+// orderedFields returns Fields in the order the fields were added to the error.
+// Fields that were added to the map afterwards are appended in sorted order.
+func (es *ErrorStack) orderedFields() []Field {
+	if len(es.Fields) == 0 {
+		return nil
+	}
+	result := make([]Field, 0, len(es.Fields))
+	seen := make(map[string]struct{}, len(es.Fields))
+	for _, field := range es.fields {
+		value, ok := es.Fields[field.Key]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[field.Key]; ok {
+			continue
+		}
+		seen[field.Key] = struct{}{}
+		result = append(result, Field{Key: field.Key, Value: value})
+	}
+	if len(result) == len(es.Fields) {
+		return result
+	}
+
+	keys := make([]string, 0, len(es.Fields)-len(result))
+	for key := range es.Fields {
+		if _, ok := seen[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		result = append(result, Field{Key: key, Value: es.Fields[key]})
+	}
+	return result
+}
+
 // GetStack returns the errors that are present in the provided error.
 func GetStack(err error) []ErrorStack {
 	if err == nil {
@@ -33,25 +82,202 @@ func GetStack(err error) []ErrorStack {
 	var collectedErrors []ErrorStack
 	for err != nil {
 		errorsToAdd := buildErrorStack(err)
+		causes := unwrapErrors(err)
+		if len(causes) > 1 {
+			// the error tree branches, every cause gets its own stack
+			errorsToAdd.Causes = make([][]ErrorStack, len(causes))
+			for i, cause := range causes {
+				errorsToAdd.Causes[i] = GetStack(cause)
+			}
+			if isJoinedMessage(errorsToAdd.ErrorMessage, causes) {
+				errorsToAdd.ErrorMessage = ""
+			}
+			collectedErrors = append(collectedErrors, errorsToAdd)
+			break
+		}
 		collectedErrors = append(collectedErrors, errorsToAdd)
-		err = errors.Unwrap(err)
+		err = nil
+		if len(causes) == 1 {
+			err = causes[0]
+		}
 	}
 
 	return cleanStack(collectedErrors)
 }
 
+// ElideCommonFrames returns a copy of the stack, where the frames at the end of each stack trace
+// that are the same as in the stack trace of the next error that has a stack trace are removed.
+// The number of removed frames is stored in FramesInCommon.
+// At least the first frame of every stack trace is kept.
+// The passed in stack is not modified, use GetStack to get the full stack traces.
+func ElideCommonFrames(stack []ErrorStack) []ErrorStack {
+	if stack == nil {
+		return nil
+	}
+	result := make([]ErrorStack, len(stack))
+	copy(result, stack)
+	for i := range result {
+		if len(result[i].Causes) > 0 {
+			causes := make([][]ErrorStack, len(result[i].Causes))
+			for j, cause := range result[i].Causes {
+				causes[j] = ElideCommonFrames(cause)
+			}
+			result[i].Causes = causes
+		}
+
+		trace := result[i].StackTrace
+		for j := i + 1; j < len(stack); j++ {
+			if len(stack[j].StackTrace) == 0 {
+				continue
+			}
+			common := countCommonFrames(trace, stack[j].StackTrace)
+			if common == len(trace) {
+				common--
+			}
+			if common > 0 {
+				result[i].StackTrace = trace[:len(trace)-common]
+				result[i].FramesInCommon = common
+			}
+			break
+		}
+	}
+	return result
+}
+
+// countCommonFrames returns the number of frames at the end of a that are the same as at the end of b.
+func countCommonFrames(a, b []StackFrame) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+// unwrapErrors returns the direct causes of the error.
+// It supports both, errors implementing Unwrap() error and errors implementing Unwrap() []error.
+func unwrapErrors(err error) []error {
+	if e, ok := err.(*Error); ok && len(e.causes) > 0 {
+		return e.causes
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		causes := x.Unwrap()
+		result := make([]error, 0, len(causes))
+		for _, cause := range causes {
+			if cause != nil {
+				result = append(result, cause)
+			}
+		}
+		return result
+	case interface{ Unwrap() error }:
+		if cause := x.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+	return nil
+}
+
+// walkErrors calls fn for every error in the error tree in depth-first pre-order,
+// so outer errors are visited before their causes and earlier causes before later ones.
+func walkErrors(err error, fn func(err error)) {
+	for err != nil {
+		fn(err)
+		causes := unwrapErrors(err)
+		if len(causes) > 1 {
+			for _, cause := range causes {
+				walkErrors(cause, fn)
+			}
+			return
+		}
+		err = nil
+		if len(causes) == 1 {
+			err = causes[0]
+		}
+	}
+}
+
+// isJoinedMessage reports whether the message is just the messages of the causes
+// joined by a newline, as errors.Join does.
+func isJoinedMessage(message string, causes []error) bool {
+	messages := make([]string, len(causes))
+	for i, cause := range causes {
+		messages[i] = cause.Error()
+	}
+	return message == strings.Join(messages, "\n")
+}
+
+// layerMessage returns the message of the error without the texts of its causes.
+// The causes of errors with multiple causes are listed in ErrorStack.Causes,
+// so their texts are removed from the message to not print them twice.
+func (e *Error) layerMessage() string {
+	if len(e.causes) == 0 {
+		return e.message
+	}
+	causes := e.causes
+	if !e.fullMessage {
+		// the first cause was passed to Wrapf and is not part of the message
+		causes = causes[1:]
+	}
+	return removeCauseTexts(e.message, causes)
+}
+
+// removeCauseTexts removes the texts of the causes from the message.
+func removeCauseTexts(message string, causes []error) string {
+	offset := 0
+	for _, cause := range causes {
+		text := cause.Error()
+		i := strings.Index(message[offset:], text)
+		if text == "" || i < 0 {
+			continue
+		}
+		i += offset
+		before := message[:i]
+		after := message[i+len(text):]
+		if before == "" || strings.HasSuffix(before, " ") {
+			after = strings.TrimLeft(after, " ")
+		}
+		message = before + after
+		offset = len(before)
+	}
+	return strings.Trim(message, " :,;\n")
+}
+
 func buildErrorStack(err error) ErrorStack {
-	if serr, ok := err.(*Error); ok {
+	switch serr := err.(type) {
+	case *Error:
 		return ErrorStack{
 			error:        err,
-			ErrorMessage: serr.message,
-			Fields:       serr.fields,
-			StackTrace:   resolveStackForStackFrames(serr.stack),
+			fields:       serr.fields,
+			ErrorMessage: serr.layerMessage(),
+			Code:         serr.code,
+			Fields:       fieldsToMap(serr.fields),
+			StackTrace:   serr.stackFrames(),
+		}
+	case *MultiError:
+		return ErrorStack{
+			error:        err,
+			fields:       serr.fields,
+			ErrorMessage: serr.summary(),
+			Fields:       fieldsToMap(serr.fields),
+			StackTrace:   nil,
 		}
 	}
 	return buildErrorStackForThirdPartyError(err)
 }
 
+// stackFrames returns the resolved stack frames of the error.
+func (e *Error) stackFrames() []StackFrame {
+	frames := e.frames
+	if e.stack != nil {
+		frames = e.stack.frames()
+	}
+	if frames == nil {
+		return nil
+	}
+	// the frames are shared between all calls, make sure the caller can not modify them
+	return append([]StackFrame(nil), frames...)
+}
+
 func cleanStack(stackFrames []ErrorStack) []ErrorStack {
 	// the following code only exists for cleaning up error formats.
 	// e.g. when using pkg/errors.Wrap function two errors are added to the stack.
@@ -86,21 +312,35 @@ func cleanStack(stackFrames []ErrorStack) []ErrorStack {
 		if len(stackFrames[i].StackTrace) == 0 && len(stackFrames[i-1].StackTrace) > 0 &&
 			stackFrames[i].ErrorMessage == stackFrames[i-1].ErrorMessage {
 			appendToFields(&stackFrames[i-1].Fields, stackFrames[i].Fields)
+			stackFrames[i-1].Causes = stackFrames[i].Causes
+			if stackFrames[i-1].Code == "" {
+				stackFrames[i-1].Code = stackFrames[i].Code
+			}
 			stackFrames = append(stackFrames[:i], stackFrames[i+1:]...)
 		}
 	}
 
-	// clean up duplicate messages
-	//nolint:gomnd // we only need to clear up when we have more than 1 stack frame
-	if size := len(stackFrames); size > 1 {
-		fullErrorText := ": " + stackFrames[size-1].ErrorMessage
-		//nolint:gomnd //iterate backwards and start at the second last stack frame
-		for i := size - 2; i >= 0; i-- {
-			s, found := strings.CutSuffix(stackFrames[i].ErrorMessage, fullErrorText)
-			if found {
-				stackFrames[i].ErrorMessage = s
-			}
-			fullErrorText = ": " + stackFrames[i].ErrorMessage
+	// clean up duplicate messages, the message of an error might contain the full text of its cause
+	// (e.g. when using fmt.Errorf("...: %w", err))
+	//nolint:gomnd //iterate backwards and start at the second last stack frame
+	for i := len(stackFrames) - 2; i >= 0; i-- {
+		if stackFrames[i+1].error == nil {
+			continue
+		}
+		fullErrorText := ": " + stackFrames[i+1].error.Error()
+		s, found := strings.CutSuffix(stackFrames[i].ErrorMessage, fullErrorText)
+		if found {
+			stackFrames[i].ErrorMessage = s
+			continue
+		}
+		// the error only adds information, but no text to its cause (e.g. Errorf("%w", err))
+		if stackFrames[i].ErrorMessage == stackFrames[i+1].error.Error() {
+			stackFrames[i].ErrorMessage = ""
+			continue
+		}
+		// the cause was referenced by a %w verb in the middle of the message
+		if e, ok := stackFrames[i].error.(*Error); ok && e.fullMessage && e.cause != nil && len(e.causes) == 0 {
+			stackFrames[i].ErrorMessage = removeCauseTexts(stackFrames[i].ErrorMessage, []error{e.cause})
 		}
 	}
 	return stackFrames
@@ -0,0 +1,151 @@
+package serrors
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultStackCacheSize is the default number of program counters whose frames are cached.
+const DefaultStackCacheSize = 4096
+
+// StackCacheStats holds the statistics of the stack frame cache.
+type StackCacheStats struct {
+	// Hits is the number of program counters that were resolved using the cache.
+	Hits uint64
+	// Misses is the number of program counters that had to be resolved using the runtime.
+	Misses uint64
+	// Size is the number of program counters that are currently cached.
+	Size int
+}
+
+// frameCache caches the frames of program counters.
+// When the cache is full it is cleared, so it never grows beyond its size.
+type frameCache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[uintptr][]StackFrame
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+var stackCache = &frameCache{
+	size:    DefaultStackCacheSize,
+	entries: make(map[uintptr][]StackFrame),
+}
+
+// SetStackCacheSize sets the number of program counters whose resolved frames are cached.
+// A size of 0 or less disables the cache. Changing the size clears the cache.
+func SetStackCacheSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	stackCache.mu.Lock()
+	defer stackCache.mu.Unlock()
+	stackCache.size = size
+	stackCache.entries = make(map[uintptr][]StackFrame)
+}
+
+// GetStackCacheStats returns the current statistics of the stack frame cache.
+func GetStackCacheStats() StackCacheStats {
+	stackCache.mu.RLock()
+	defer stackCache.mu.RUnlock()
+	return StackCacheStats{
+		Hits:   stackCache.hits.Load(),
+		Misses: stackCache.misses.Load(),
+		Size:   len(stackCache.entries),
+	}
+}
+
+// forEachFrame calls fn for every frame of the program counters until fn returns false.
+func (c *frameCache) forEachFrame(pcs []uintptr, fn func(frame StackFrame) bool) {
+	c.mu.RLock()
+	enabled := c.size > 0
+	c.mu.RUnlock()
+	if !enabled {
+		c.misses.Add(uint64(len(pcs)))
+		frames := runtime.CallersFrames(pcs)
+		for {
+			frame, more := frames.Next()
+			if !fn(StackFrame{File: frame.File, Func: frame.Function, Line: frame.Line}) || !more {
+				return
+			}
+		}
+	}
+	for _, pc := range pcs {
+		for _, frame := range c.framesForPC(pc) {
+			if !fn(frame) {
+				return
+			}
+		}
+	}
+}
+
+// framesForPC returns the frames for the program counter, a program counter can have
+// multiple frames if functions were inlined.
+// The returned slice must not be modified.
+func (c *frameCache) framesForPC(pc uintptr) []StackFrame {
+	c.mu.RLock()
+	frames, ok := c.entries[pc]
+	c.mu.RUnlock()
+	if ok {
+		c.hits.Add(1)
+		return frames
+	}
+	c.misses.Add(1)
+
+	iter := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, StackFrame{
+			File: frame.File,
+			Func: frame.Function,
+			Line: frame.Line,
+		})
+		if !more {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size > 0 {
+		if len(c.entries) >= c.size {
+			c.entries = make(map[uintptr][]StackFrame)
+		}
+		c.entries[pc] = frames
+	}
+	return frames
+}
+
+// stack holds the program counters of an error and memoizes their resolution.
+// It is shared between an error and its copies.
+type stack struct {
+	pcs      []uintptr
+	opts     stackOptions
+	resolved atomic.Pointer[resolvedStack]
+}
+
+// resolvedStack holds the frames of a stack, that were resolved with the filters.
+// If filters is nil the frames were resolved when the error was created (see FilterAtCapture).
+type resolvedStack struct {
+	filters *[]FrameFilter
+	frames  []StackFrame
+}
+
+// frames returns the resolved frames of the stack.
+// The frames are resolved once for the set of frame filters, the returned slice must not be modified.
+func (s *stack) frames() []StackFrame {
+	filters := loadFrameFilters()
+	if resolved := s.resolved.Load(); resolved != nil && (resolved.filters == nil || resolved.filters == filters) {
+		return resolved.frames
+	}
+	frames := resolveStackForStackFrames(s.pcs, s.opts)
+	s.resolved.Store(&resolvedStack{filters: filters, frames: frames})
+	return frames
+}
+
+// resolveNow resolves the frames with the current filters, they will be used even if the filters change.
+func (s *stack) resolveNow() {
+	s.resolved.Store(&resolvedStack{filters: nil, frames: resolveStackForStackFrames(s.pcs, s.opts)})
+}
@@ -0,0 +1,177 @@
+package serrors
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// StackMode controls how stacks are captured for new errors.
+type StackMode int
+
+const (
+	// StackFull captures the full stack for every error. This is the default.
+	StackFull StackMode = iota
+	// StackCaller captures only the frame of the caller for every error.
+	StackCaller
+	// StackOff captures no stack at all.
+	StackOff
+	// StackSampled captures the full stack for one in N errors (see SetStackSampleRate),
+	// all other errors capture only the frame of the caller.
+	StackSampled
+)
+
+// DefaultStackSampleRate is the default sample rate for StackSampled.
+const DefaultStackSampleRate = 100
+
+// Environment variables that are used to set the stack mode,
+// they are read when the stack mode is used or set for the first time.
+const (
+	// StackModeEnv is the name of the environment variable that sets the StackMode,
+	// valid values are "full", "caller", "off" and "sampled".
+	StackModeEnv = "SERRORS_STACK_MODE"
+	// StackSampleRateEnv is the name of the environment variable that sets the sample rate for StackSampled.
+	StackSampleRateEnv = "SERRORS_STACK_SAMPLE_RATE"
+)
+
+var (
+	stackMode       atomic.Int64
+	stackSampleRate atomic.Int64
+	stackSamples    atomic.Uint64
+	stackModeEnv    sync.Once
+)
+
+// loadStackModeFromEnv reads the environment variables once,
+// so they are applied before the stack mode is used or set for the first time.
+func loadStackModeFromEnv() {
+	stackModeEnv.Do(func() {
+		_ = stackModeFromEnv()
+	})
+}
+
+// SetStackMode sets how stacks are captured for new errors.
+func SetStackMode(mode StackMode) {
+	loadStackModeFromEnv()
+	stackMode.Store(int64(mode))
+}
+
+// GetStackMode returns the current StackMode.
+func GetStackMode() StackMode {
+	loadStackModeFromEnv()
+	return StackMode(stackMode.Load())
+}
+
+// SetStackSampleRate sets N for StackSampled, the full stack is captured for one in N errors.
+// A rate of 0 or less restores DefaultStackSampleRate.
+func SetStackSampleRate(n int) {
+	loadStackModeFromEnv()
+	storeStackSampleRate(n)
+}
+
+func storeStackSampleRate(n int) {
+	if n < 0 {
+		n = 0
+	}
+	stackSampleRate.Store(int64(n))
+}
+
+// SetStackModeFromEnv sets the StackMode and the sample rate from the environment variables
+// StackModeEnv and StackSampleRateEnv. Unset variables are ignored.
+// The variables are also read when the stack mode is used or set for the first time,
+// invalid values are ignored in that case.
+func SetStackModeFromEnv() error {
+	loadStackModeFromEnv()
+	return stackModeFromEnv()
+}
+
+func stackModeFromEnv() error {
+	if s, ok := os.LookupEnv(StackModeEnv); ok {
+		mode, err := ParseStackMode(s)
+		if err != nil {
+			return err
+		}
+		stackMode.Store(int64(mode))
+	}
+	if s, ok := os.LookupEnv(StackSampleRateEnv); ok {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", StackSampleRateEnv, err)
+		}
+		storeStackSampleRate(n)
+	}
+	return nil
+}
+
+// ParseStackMode parses the text representation of a StackMode.
+func ParseStackMode(s string) (StackMode, error) {
+	var mode StackMode
+	err := mode.UnmarshalText([]byte(s))
+	return mode, err
+}
+
+// String returns the text representation of the StackMode.
+func (m StackMode) String() string {
+	switch m {
+	case StackFull:
+		return "full"
+	case StackCaller:
+		return "caller"
+	case StackOff:
+		return "off"
+	case StackSampled:
+		return "sampled"
+	}
+	return "StackMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m StackMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so a StackMode can be used in configuration files.
+func (m *StackMode) UnmarshalText(text []byte) error {
+	switch strings.ToLower(strings.TrimSpace(string(text))) {
+	case "full", "":
+		*m = StackFull
+	case "caller":
+		*m = StackCaller
+	case "off":
+		*m = StackOff
+	case "sampled":
+		*m = StackSampled
+	default:
+		return fmt.Errorf("unknown stack mode %q", text)
+	}
+	return nil
+}
+
+// forMode returns the stack options for the current StackMode,
+// it returns false if no stack should be captured.
+func (opts stackOptions) forMode() (stackOptions, bool) {
+	if opts.off {
+		return opts, false
+	}
+	switch GetStackMode() {
+	case StackFull:
+		return opts, true
+	case StackCaller:
+		opts.depth = 1
+		return opts, true
+	case StackOff:
+		return opts, false
+	case StackSampled:
+		rate := stackSampleRate.Load()
+		if rate == 0 {
+			rate = DefaultStackSampleRate
+		}
+		if stackSamples.Add(1)%uint64(rate) != 0 {
+			opts.depth = 1
+		}
+		return opts, true
+	}
+	return opts, true
+}
@@ -0,0 +1,84 @@
+//go:build !serrors_without_stack
+// +build !serrors_without_stack
+
+package serrors_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func TestStackMode(t *testing.T) {
+	defer serrors.SetStackMode(serrors.StackFull)
+
+	stackTraceLen := func() int {
+		return len(serrors.GetStack(newErrorWithBuilder(serrors.NewBuilder()))[0].StackTrace)
+	}
+
+	t.Run("full", func(t *testing.T) {
+		serrors.SetStackMode(serrors.StackFull)
+		Equal(t, true, stackTraceLen() > 1)
+	})
+
+	t.Run("caller", func(t *testing.T) {
+		serrors.SetStackMode(serrors.StackCaller)
+		Equal(t, 1, stackTraceLen())
+	})
+
+	t.Run("off", func(t *testing.T) {
+		serrors.SetStackMode(serrors.StackOff)
+		Equal(t, 0, stackTraceLen())
+		Equal(t, "some error", serrors.Wrap(serrors.New("cause"), "some error").Error()[:10])
+	})
+
+	t.Run("sampled", func(t *testing.T) {
+		serrors.SetStackMode(serrors.StackSampled)
+		serrors.SetStackSampleRate(2)
+		defer serrors.SetStackSampleRate(0)
+
+		full := 0
+		for i := 0; i < 4; i++ {
+			if stackTraceLen() > 1 {
+				full++
+			}
+		}
+		Equal(t, 2, full)
+	})
+}
+
+func TestSetStackModeFromEnv(t *testing.T) {
+	defer serrors.SetStackMode(serrors.StackFull)
+	defer serrors.SetStackSampleRate(0)
+
+	t.Setenv(serrors.StackModeEnv, "sampled")
+	t.Setenv(serrors.StackSampleRateEnv, "10")
+	Nil(t, serrors.SetStackModeFromEnv())
+	Equal(t, serrors.StackSampled, serrors.GetStackMode())
+
+	t.Setenv(serrors.StackModeEnv, "unknown")
+	NotNil(t, serrors.SetStackModeFromEnv())
+	Equal(t, serrors.StackSampled, serrors.GetStackMode())
+
+	t.Setenv(serrors.StackModeEnv, "off")
+	t.Setenv(serrors.StackSampleRateEnv, "ten")
+	NotNil(t, serrors.SetStackModeFromEnv())
+}
+
+func TestParseStackMode(t *testing.T) {
+	for _, mode := range []serrors.StackMode{serrors.StackFull, serrors.StackCaller, serrors.StackOff, serrors.StackSampled} {
+		parsed, err := serrors.ParseStackMode(mode.String())
+		Nil(t, err)
+		Equal(t, mode, parsed)
+	}
+
+	_, err := serrors.ParseStackMode("unknown")
+	NotNil(t, err)
+
+	var config struct {
+		Mode serrors.StackMode `json:"mode"`
+	}
+	Nil(t, json.Unmarshal([]byte(`{"mode":"Caller"}`), &config))
+	Equal(t, serrors.StackCaller, config.Mode)
+}
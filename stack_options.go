@@ -0,0 +1,120 @@
+package serrors
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultStackDepth is the default maximum number of stack frames that are captured for an error.
+const DefaultStackDepth = 64
+
+// internalFrames is the number of additional frames that are captured to make room for
+// the frames of this package, which are removed when the stack is resolved.
+const internalFrames = 8
+
+var (
+	stackDepth atomic.Int64
+	stackSkip  atomic.Int64
+)
+
+// SetStackDepth sets the maximum number of stack frames that are captured for an error.
+// A depth of 0 or less restores DefaultStackDepth.
+// The depth can be set for a single error with ErrorBuilder.WithStackDepth.
+func SetStackDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	stackDepth.Store(int64(depth))
+}
+
+// SetStackSkip sets the number of caller frames that are skipped for every error.
+// Additional frames can be skipped for a single error with ErrorBuilder.WithStackSkip.
+func SetStackSkip(skip int) {
+	if skip < 0 {
+		skip = 0
+	}
+	stackSkip.Store(int64(skip))
+}
+
+// stackOptions controls how the stack of an error is captured and resolved.
+type stackOptions struct {
+	// depth is the maximum number of frames, 0 means no limit.
+	depth int
+	// skip is the number of caller frames that are skipped.
+	skip int
+	// off disables the stack capture.
+	off bool
+}
+
+func currentStackOptions() stackOptions {
+	depth := int(stackDepth.Load())
+	if depth == 0 {
+		depth = DefaultStackDepth
+	}
+	return stackOptions{
+		depth: depth,
+		skip:  int(stackSkip.Load()),
+	}
+}
+
+// WithStackDepth returns a copy of the ErrorBuilder that captures at most depth stack frames
+// for every error it creates. A depth of 0 or less uses the depth set by SetStackDepth.
+func (eb *ErrorBuilder) WithStackDepth(depth int) *ErrorBuilder {
+	clone := *eb
+	clone.stackDepth = depth
+	return &clone
+}
+
+// WithStackSkip returns a copy of the ErrorBuilder that skips additional skip caller frames
+// for every error it creates. This can be used by functions that create errors on behalf of their caller.
+func (eb *ErrorBuilder) WithStackSkip(skip int) *ErrorBuilder {
+	clone := *eb
+	clone.stackSkip = skip
+	return &clone
+}
+
+// WithoutStack returns a copy of the ErrorBuilder that creates errors without a stack.
+func (eb *ErrorBuilder) WithoutStack() *ErrorBuilder {
+	clone := *eb
+	clone.withoutStack = true
+	return &clone
+}
+
+func (eb *ErrorBuilder) stackOptions() stackOptions {
+	opts := currentStackOptions()
+	if eb.stackDepth > 0 {
+		opts.depth = eb.stackDepth
+	}
+	if eb.stackSkip > 0 {
+		opts.skip += eb.stackSkip
+	}
+	opts.off = eb.withoutStack
+	return opts
+}
+
+var (
+	helperPCs   sync.Map // map[uintptr]struct{}
+	helperFuncs sync.Map // map[string]struct{}
+)
+
+// Helper marks the calling function as a helper function.
+// Helper functions on top of the stack of an error are skipped, so the reported top frame is
+// the caller of the helper. This is similar to testing.T.Helper.
+func Helper() {
+	var pcs [1]uintptr
+	// skip runtime.Callers and Helper
+	if runtime.Callers(2, pcs[:]) == 0 { //nolint:gomnd // see comment above
+		return
+	}
+	if _, loaded := helperPCs.LoadOrStore(pcs[0], struct{}{}); loaded {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	helperFuncs.Store(frame.Function, struct{}{})
+}
+
+func isHelper(function string) bool {
+	_, ok := helperFuncs.Load(function)
+	return ok
+}
@@ -0,0 +1,100 @@
+package serrors_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/Eun/serrors"
+)
+
+func newErrorInHelper() *serrors.Error {
+	serrors.Helper()
+	return serrors.New("error in helper")
+}
+
+func newErrorInNestedHelper() *serrors.Error {
+	serrors.Helper()
+	return newErrorInHelper()
+}
+
+func newErrorWithSkip() *serrors.Error {
+	return serrors.NewBuilder().WithStackSkip(1).New("error with skip")
+}
+
+func newErrorWithBuilder(eb *serrors.ErrorBuilder) *serrors.Error {
+	return eb.New("error with builder")
+}
+
+func recursiveError(n int) *serrors.Error {
+	if n == 0 {
+		return serrors.New("recursive error")
+	}
+	return recursiveError(n - 1)
+}
+
+func TestStackOptions(t *testing.T) {
+	skipWithoutStack(t)
+
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	t.Run("SetStackDepth", func(t *testing.T) {
+		serrors.SetStackDepth(1)
+		defer serrors.SetStackDepth(0)
+
+		err := serrors.New("some error") // [TestStackOptions00]
+		CompareErrorStack(t, []serrors.ErrorStack{
+			{
+				ErrorMessage: "some error",
+				StackTrace: []serrors.StackFrame{
+					buildStackFrameFromMarker(t, filename, "TestStackOptions00"),
+				},
+			},
+		}, serrors.GetStack(err))
+	})
+
+	t.Run("SetStackDepth allows deep stacks", func(t *testing.T) {
+		const depth = 100
+		Equal(t, true, len(serrors.GetStack(recursiveError(depth))[0].StackTrace) <= serrors.DefaultStackDepth)
+
+		serrors.SetStackDepth(depth * 2)
+		defer serrors.SetStackDepth(0)
+		Equal(t, true, len(serrors.GetStack(recursiveError(depth))[0].StackTrace) > depth)
+	})
+
+	t.Run("SetStackSkip", func(t *testing.T) {
+		serrors.SetStackSkip(1)
+		defer serrors.SetStackSkip(0)
+
+		err := newErrorInTest(t) // [TestStackOptions01]
+		stack := serrors.GetStack(err)
+		Equal(t, buildStackFrameFromMarker(t, filename, "TestStackOptions01"), stack[0].StackTrace[0])
+	})
+
+	t.Run("WithStackDepth", func(t *testing.T) {
+		Equal(t, 2, len(serrors.GetStack(newErrorWithBuilder(serrors.NewBuilder()))[0].StackTrace))
+		Equal(t, 1, len(serrors.GetStack(newErrorWithBuilder(serrors.NewBuilder().WithStackDepth(1)))[0].StackTrace))
+	})
+
+	t.Run("WithStackSkip", func(t *testing.T) {
+		err := newErrorWithSkip() // [TestStackOptions02]
+		stack := serrors.GetStack(err)
+		Equal(t, buildStackFrameFromMarker(t, filename, "TestStackOptions02"), stack[0].StackTrace[0])
+	})
+
+	t.Run("Helper", func(t *testing.T) {
+		err := newErrorInHelper() // [TestStackOptions03]
+		stack := serrors.GetStack(err)
+		Equal(t, buildStackFrameFromMarker(t, filename, "TestStackOptions03"), stack[0].StackTrace[0])
+	})
+
+	t.Run("nested Helper", func(t *testing.T) {
+		err := newErrorInNestedHelper() // [TestStackOptions04]
+		stack := serrors.GetStack(err)
+		Equal(t, buildStackFrameFromMarker(t, filename, "TestStackOptions04"), stack[0].StackTrace[0])
+	})
+}
+
+func newErrorInTest(*testing.T) *serrors.Error {
+	return serrors.New("some error")
+}
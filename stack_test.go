@@ -1,11 +1,15 @@
 package serrors_test
 
 import (
+	"errors"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Eun/serrors"
@@ -27,6 +31,82 @@ func TestGetStack(t *testing.T) {
 	})
 }
 
+func TestGetStack_MultipleCauses(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	t.Run("joined", func(t *testing.T) {
+		err := serrors.Wrap(errors.Join( // [TestGetStack_MultipleCauses00]
+			serrors.New("error 1").With("k1", "v1"), // [TestGetStack_MultipleCauses01]
+			errors.New("error 2"),
+		), "some error")
+
+		expectedStack := []serrors.ErrorStack{
+			{
+				ErrorMessage: "some error",
+				StackTrace: []serrors.StackFrame{
+					buildStackFrameFromMarker(t, filename, "TestGetStack_MultipleCauses00"),
+				},
+			},
+			{
+				ErrorMessage: "",
+				Causes: [][]serrors.ErrorStack{
+					{
+						{
+							ErrorMessage: "error 1",
+							Fields:       map[string]any{"k1": "v1"},
+							StackTrace: []serrors.StackFrame{
+								buildStackFrameFromMarker(t, filename, "TestGetStack_MultipleCauses01"),
+							},
+						},
+					},
+					{
+						{
+							ErrorMessage: "error 2",
+						},
+					},
+				},
+			},
+		}
+		skipWithoutStack(t)
+		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
+	})
+
+	t.Run("multiple %w", func(t *testing.T) {
+		err := fmt.Errorf("some error: %w, %w",
+			errors.New("error 1"),
+			fmt.Errorf("error 2: %w", errors.New("error 3")),
+		)
+
+		expectedStack := []serrors.ErrorStack{
+			{
+				ErrorMessage: "some error: error 1, error 2: error 3",
+				Causes: [][]serrors.ErrorStack{
+					{
+						{ErrorMessage: "error 1"},
+					},
+					{
+						{ErrorMessage: "error 2"},
+						{ErrorMessage: "error 3"},
+					},
+				},
+			},
+		}
+		CompareErrorStack(t, expectedStack, serrors.GetStack(err))
+	})
+}
+
+func TestGetStack_DuplicateMessages(t *testing.T) {
+	err := fmt.Errorf("error 1: %w", fmt.Errorf("error 2: %w", errors.New("error 3")))
+
+	expectedStack := []serrors.ErrorStack{
+		{ErrorMessage: "error 1"},
+		{ErrorMessage: "error 2"},
+		{ErrorMessage: "error 3"},
+	}
+	CompareErrorStack(t, expectedStack, serrors.GetStack(err))
+}
+
 func buildStackFrameFromMarker(t *testing.T, fileName, marker string) serrors.StackFrame {
 	marker = "[" + marker + "]"
 	var result *serrors.StackFrame
@@ -79,6 +159,8 @@ func findEnclosingFunc(fileSet *token.FileSet, file *ast.File, offset int) strin
 	ast.Inspect(file, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.FuncDecl:
+			// anonymous functions are numbered per top level function
+			anonCounter = 1
 			pos := fileSet.Position(node.Pos()).Offset
 			end := fileSet.Position(node.End()).Offset
 
@@ -100,3 +182,102 @@ func findEnclosingFunc(fileSet *token.FileSet, file *ast.File, offset int) strin
 
 	return strings.Join(result, ".")
 }
+
+func TestStackCache(t *testing.T) {
+	skipWithoutStack(t)
+
+	newError := func() error {
+		return serrors.Wrap(serrors.New("error 1"), "error 2")
+	}
+
+	t.Run("hits", func(t *testing.T) {
+		serrors.SetStackCacheSize(serrors.DefaultStackCacheSize)
+		var stats []serrors.StackCacheStats
+		for i := 0; i < 2; i++ {
+			serrors.GetStack(newError())
+			stats = append(stats, serrors.GetStackCacheStats())
+		}
+		Equal(t, true, stats[0].Size > 0)
+		Equal(t, true, stats[1].Hits > stats[0].Hits)
+		Equal(t, stats[0].Misses, stats[1].Misses)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		serrors.SetStackCacheSize(0)
+		defer serrors.SetStackCacheSize(serrors.DefaultStackCacheSize)
+
+		err := newError()
+		stack := serrors.GetStack(err)
+		Equal(t, 0, serrors.GetStackCacheStats().Size)
+		CompareErrorStack(t, stack, serrors.GetStack(err))
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		serrors.SetStackCacheSize(1)
+		defer serrors.SetStackCacheSize(serrors.DefaultStackCacheSize)
+
+		serrors.GetStack(newError())
+		Equal(t, 1, serrors.GetStackCacheStats().Size)
+	})
+
+	t.Run("modifying the stack does not modify the error", func(t *testing.T) {
+		err := newError()
+		stack := serrors.GetStack(err)
+		stack[0].StackTrace[0].Line = -1
+		Equal(t, true, serrors.GetStack(err)[0].StackTrace[0].Line > 0)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		err := newError()
+		expected := serrors.GetStack(err)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				CompareErrorStack(t, expected, serrors.GetStack(err))
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func BenchmarkGetStack(b *testing.B) {
+	newError := func() error {
+		return serrors.Wrap(serrors.Wrap(serrors.New("error 1"), "error 2"), "error 3")
+	}
+
+	b.Run("same error", func(b *testing.B) {
+		err := newError()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			serrors.GetStack(err)
+		}
+	})
+
+	b.Run("new errors", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			serrors.GetStack(newError())
+		}
+	})
+
+	b.Run("new errors without cache", func(b *testing.B) {
+		serrors.SetStackCacheSize(0)
+		defer serrors.SetStackCacheSize(serrors.DefaultStackCacheSize)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			serrors.GetStack(newError())
+		}
+	})
+}
+
+func BenchmarkFormat(b *testing.B) {
+	err := serrors.Wrap(serrors.Wrap(serrors.New("error 1"), "error 2"), "error 3")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%+v", err)
+	}
+}
@@ -1,31 +1,301 @@
 package serrors
 
 import (
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
 	pkgerrors "github.com/pkg/errors"
 )
 
+// StackExtractor returns the stack frames of an error that was not created by this package.
+// It returns false if it does not support the error.
+type StackExtractor func(err error) ([]StackFrame, bool)
+
+// FieldsExtractor returns the fields of an error that was not created by this package.
+// It returns false if it does not support the error.
+type FieldsExtractor func(err error) ([]Field, bool)
+
+var (
+	extractorsMu     sync.Mutex
+	stackExtractors  atomic.Pointer[[]StackExtractor]
+	fieldsExtractors atomic.Pointer[[]FieldsExtractor]
+
+	defaultStackExtractors  = DefaultStackExtractors()
+	defaultFieldsExtractors = DefaultFieldsExtractors()
+)
+
+// DefaultStackExtractors returns the stack extractors that are used if SetStackExtractors was not called.
+// They support errors with a StackTrace() method (e.g. pkg/errors), a Callers() []uintptr method
+// and a StackFrames() method (e.g. go-errors), in this order.
+func DefaultStackExtractors() []StackExtractor {
+	return []StackExtractor{
+		StackFromStackTrace,
+		StackFromCallers,
+		StackFromStackFrames,
+	}
+}
+
+// DefaultFieldsExtractors returns the fields extractors that are used if SetFieldsExtractors was not called.
+// They support errors with a Fields() map[string]any method and errors that implement slog.LogValuer,
+// in this order.
+func DefaultFieldsExtractors() []FieldsExtractor {
+	return []FieldsExtractor{
+		FieldsFromFieldsMethod,
+		FieldsFromLogValuer,
+	}
+}
+
+// SetStackExtractors replaces all stack extractors with the passed in extractors.
+// The extractors are called in order, the first extractor that supports an error is used.
+func SetStackExtractors(extractors ...StackExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append([]StackExtractor(nil), extractors...)
+	stackExtractors.Store(&extractors)
+}
+
+// AddStackExtractor adds a stack extractor that takes precedence over the already set extractors.
+func AddStackExtractor(extractor StackExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	current := currentStackExtractors()
+	extractors := make([]StackExtractor, 0, len(current)+1)
+	extractors = append(extractors, extractor)
+	extractors = append(extractors, current...)
+	stackExtractors.Store(&extractors)
+}
+
+// SetFieldsExtractors replaces all fields extractors with the passed in extractors.
+// The extractors are called in order, the first extractor that supports an error is used.
+func SetFieldsExtractors(extractors ...FieldsExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append([]FieldsExtractor(nil), extractors...)
+	fieldsExtractors.Store(&extractors)
+}
+
+// AddFieldsExtractor adds a fields extractor that takes precedence over the already set extractors.
+func AddFieldsExtractor(extractor FieldsExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	current := currentFieldsExtractors()
+	extractors := make([]FieldsExtractor, 0, len(current)+1)
+	extractors = append(extractors, extractor)
+	extractors = append(extractors, current...)
+	fieldsExtractors.Store(&extractors)
+}
+
+func currentStackExtractors() []StackExtractor {
+	if extractors := stackExtractors.Load(); extractors != nil {
+		return *extractors
+	}
+	return defaultStackExtractors
+}
+
+func currentFieldsExtractors() []FieldsExtractor {
+	if extractors := fieldsExtractors.Load(); extractors != nil {
+		return *extractors
+	}
+	return defaultFieldsExtractors
+}
+
+func extractStack(err error) []StackFrame {
+	for _, extractor := range currentStackExtractors() {
+		if frames, ok := extractor(err); ok {
+			return frames
+		}
+	}
+	return nil
+}
+
+func extractFields(err error) []Field {
+	for _, extractor := range currentFieldsExtractors() {
+		if fields, ok := extractor(err); ok {
+			return fields
+		}
+	}
+	return nil
+}
+
 func buildErrorStackForThirdPartyError(err error) ErrorStack {
-	es := ErrorStack{
+	fields := extractFields(err)
+	return ErrorStack{
 		error:        err,
+		fields:       fields,
 		ErrorMessage: err.Error(),
-		Fields:       nil,
-		StackTrace:   nil,
+		Fields:       fieldsToMap(fields),
+		StackTrace:   extractStack(err),
 	}
-	// pkgerrors
-	{
-		type stackTracer interface {
-			StackTrace() pkgerrors.StackTrace
+}
+
+// StackFromStackTrace is a StackExtractor for errors with a StackTrace() method that returns
+// a slice of program counters, e.g. errors created by github.com/pkg/errors.
+func StackFromStackTrace(err error) ([]StackFrame, bool) {
+	if tracer, ok := err.(interface{ StackTrace() pkgerrors.StackTrace }); ok {
+		stackTrace := tracer.StackTrace()
+		pcs := make([]uintptr, len(stackTrace))
+		for i, frame := range stackTrace {
+			pcs[i] = uintptr(frame)
 		}
+		return resolveStackForStackFrames(pcs, stackOptions{}), true
+	}
+	result, ok := callSliceMethod(methodOf(err, func(v reflect.Value) reflect.Value {
+		return v.MethodByName("StackTrace")
+	}))
+	if !ok {
+		return nil, false
+	}
+	pcs, ok := programCounters(result)
+	if !ok {
+		return nil, false
+	}
+	return resolveStackForStackFrames(pcs, stackOptions{}), true
+}
 
-		if tracer, ok := err.(stackTracer); ok {
-			stackTrace := tracer.StackTrace()
-			result := make([]uintptr, len(stackTrace))
-			for i, frame := range stackTrace {
-				result[i] = uintptr(frame)
+// StackFromCallers is a StackExtractor for errors with a Callers() []uintptr method.
+func StackFromCallers(err error) ([]StackFrame, bool) {
+	if callers, ok := err.(interface{ Callers() []uintptr }); ok {
+		return resolveStackForStackFrames(callers.Callers(), stackOptions{}), true
+	}
+	return nil, false
+}
+
+// StackFromStackFrames is a StackExtractor for errors with a StackFrames() method that returns
+// a slice of structs, e.g. errors created by github.com/go-errors/errors.
+// The structs must either have a program counter field (ProgramCounter or PC),
+// or a file (File), a line (Line or LineNumber) and a function (Func, Function or Name and Package) field.
+func StackFromStackFrames(err error) ([]StackFrame, bool) {
+	result, ok := callSliceMethod(methodOf(err, func(v reflect.Value) reflect.Value {
+		return v.MethodByName("StackFrames")
+	}))
+	if !ok {
+		return nil, false
+	}
+	if pcs, ok := programCounters(result); ok {
+		return resolveStackForStackFrames(pcs, stackOptions{}), true
+	}
+	if result.Type().Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	pcs := make([]uintptr, 0, result.Len())
+	frames := make([]StackFrame, 0, result.Len())
+	for i := 0; i < result.Len(); i++ {
+		elem := result.Index(i)
+		if pc, ok := uintptrField(elem, "ProgramCounter", "PC"); ok {
+			pcs = append(pcs, pc)
+			continue
+		}
+		frame := StackFrame{
+			File: stringField(elem, "File"),
+			Func: stringField(elem, "Func", "Function"),
+			Line: int(intField(elem, "Line", "LineNumber")),
+		}
+		if frame.Func == "" {
+			frame.Func = stringField(elem, "Name")
+			if pkg := stringField(elem, "Package"); pkg != "" && frame.Func != "" {
+				frame.Func = pkg + "." + frame.Func
 			}
-			es.StackTrace = resolveStackForStackFrames(result)
+		}
+		if frame.File == "" && frame.Func == "" {
+			return nil, false
+		}
+		frames = append(frames, frame)
+	}
+	if len(pcs) > 0 {
+		return resolveStackForStackFrames(pcs, stackOptions{}), true
+	}
+	return filterStackFrames(frames), true
+}
+
+// FieldsFromFieldsMethod is a FieldsExtractor for errors with a Fields() map[string]any method.
+// The fields are sorted by their key.
+func FieldsFromFieldsMethod(err error) ([]Field, bool) {
+	if f, ok := err.(interface{ Fields() map[string]any }); ok {
+		return mapToFields(f.Fields()), true
+	}
+	return nil, false
+}
+
+// FieldsFromLogValuer is a FieldsExtractor for errors that implement slog.LogValuer and
+// return a group, every attribute of the group is used as a field.
+func FieldsFromLogValuer(err error) ([]Field, bool) {
+	valuer, ok := err.(slog.LogValuer)
+	if !ok {
+		return nil, false
+	}
+	value := valuer.LogValue().Resolve()
+	if value.Kind() != slog.KindGroup {
+		return nil, false
+	}
+	attrs := value.Group()
+	fields := make([]Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = append(fields, Field{Key: attr.Key, Value: attr.Value.Resolve().Any()})
+	}
+	return fields, true
+}
+
+// methodOf returns the method that lookup returns for the error, or an invalid value if the error
+// is a nil pointer. The method name must be a constant in lookup, so the linker can still remove
+// unused methods.
+func methodOf(err error, lookup func(v reflect.Value) reflect.Value) reflect.Value {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return reflect.Value{}
+	}
+	return lookup(v)
+}
+
+// callSliceMethod calls a method without arguments that returns a slice.
+func callSliceMethod(method reflect.Value) (reflect.Value, bool) {
+	if !method.IsValid() {
+		return reflect.Value{}, false
+	}
+	t := method.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice {
+		return reflect.Value{}, false
+	}
+	return method.Call(nil)[0], true
+}
+
+// programCounters converts a slice of uintptr kinds into program counters.
+func programCounters(slice reflect.Value) ([]uintptr, bool) {
+	if slice.Type().Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+	pcs := make([]uintptr, slice.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(slice.Index(i).Uint())
+	}
+	return pcs, true
+}
+
+func uintptrField(v reflect.Value, names ...string) (uintptr, bool) {
+	for _, name := range names {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.Uintptr {
+			return uintptr(f.Uint()), true
+		}
+	}
+	return 0, false
+}
+
+func stringField(v reflect.Value, names ...string) string {
+	for _, name := range names {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
 		}
 	}
+	return ""
+}
 
-	return es
+func intField(v reflect.Value, names ...string) int64 {
+	for _, name := range names {
+		if f := v.FieldByName(name); f.IsValid() && f.CanInt() {
+			return f.Int()
+		}
+	}
+	return 0
 }
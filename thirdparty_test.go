@@ -3,6 +3,7 @@ package serrors_test
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"runtime"
 	"testing"
 
@@ -12,6 +13,8 @@ import (
 )
 
 func TestGetStack_WithPkgErrors(t *testing.T) {
+	skipWithoutStack(t)
+
 	_, filename, _, ok := runtime.Caller(0)
 	Equal(t, true, ok)
 
@@ -45,6 +48,8 @@ func TestGetStack_WithPkgErrors(t *testing.T) {
 }
 
 func TestError_Format_WithPkgErrors(t *testing.T) {
+	skipWithoutStack(t)
+
 	_, filename, _, ok := runtime.Caller(0)
 	Equal(t, true, ok)
 
@@ -72,3 +77,145 @@ func TestError_Error_WithPkgErrors(t *testing.T) {
 
 	Equal(t, "serrors: pkgerrors: errors", err.Error())
 }
+
+type callersError struct {
+	pcs []uintptr
+}
+
+func (*callersError) Error() string        { return "callers error" }
+func (e *callersError) Callers() []uintptr { return e.pcs }
+
+func newCallersError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs) // [newCallersError00]
+	return &callersError{pcs: pcs[:n]}
+}
+
+type customFrame uintptr
+
+type customStackTraceError struct {
+	frames []customFrame
+}
+
+func (*customStackTraceError) Error() string               { return "custom stack trace error" }
+func (e *customStackTraceError) StackTrace() []customFrame { return e.frames }
+
+func newCustomStackTraceError() error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs) // [newCustomStackTraceError00]
+	frames := make([]customFrame, n)
+	for i := range frames {
+		frames[i] = customFrame(pcs[i])
+	}
+	return &customStackTraceError{frames: frames}
+}
+
+type goErrorsFrame struct {
+	File       string
+	LineNumber int
+	Name       string
+	Package    string
+}
+
+type stackFramesError struct{}
+
+func (stackFramesError) Error() string { return "stack frames error" }
+func (stackFramesError) StackFrames() []goErrorsFrame {
+	return []goErrorsFrame{
+		{File: "/src/main.go", LineNumber: 10, Name: "run", Package: "main"},
+		{File: "/go/src/runtime/proc.go", LineNumber: 20, Name: "main", Package: "runtime"},
+	}
+}
+
+type fieldsError struct{}
+
+func (fieldsError) Error() string { return "fields error" }
+func (fieldsError) Fields() map[string]any {
+	return map[string]any{"k2": "v2", "k1": "v1"}
+}
+
+type logValuerError struct{}
+
+func (logValuerError) Error() string { return "log valuer error" }
+func (logValuerError) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("k1", "v1"), slog.Int("k2", 2))
+}
+
+func TestExtractors(t *testing.T) {
+	_, filename, _, ok := runtime.Caller(0)
+	Equal(t, true, ok)
+
+	t.Run("Callers", func(t *testing.T) {
+		skipWithoutStack(t)
+		stack := serrors.GetStack(newCallersError())
+		Equal(t, buildStackFrameFromMarker(t, filename, "newCallersError00"), stack[0].StackTrace[0])
+	})
+
+	t.Run("StackTrace with custom frames", func(t *testing.T) {
+		skipWithoutStack(t)
+		stack := serrors.GetStack(newCustomStackTraceError())
+		Equal(t, buildStackFrameFromMarker(t, filename, "newCustomStackTraceError00"), stack[0].StackTrace[0])
+	})
+
+	t.Run("StackFrames", func(t *testing.T) {
+		CompareErrorStack(t, []serrors.ErrorStack{
+			{
+				ErrorMessage: "stack frames error",
+				StackTrace: []serrors.StackFrame{
+					{File: "/src/main.go", Func: "main.run", Line: 10},
+				},
+			},
+		}, serrors.GetStack(stackFramesError{}))
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		err := serrors.Wrap(fieldsError{}, "some error").With("k1", "outer")
+		Equal(t, []serrors.Field{
+			{Key: "k1", Value: "outer"},
+			{Key: "k2", Value: "v2"},
+		}, serrors.GetOrderedFields(err))
+		Equal(t, map[string]any{"k1": "v1", "k2": "v2"}, serrors.GetStack(err)[1].Fields)
+	})
+
+	t.Run("LogValuer", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", logValuerError{})
+		Equal(t, map[string]any{"k1": "v1", "k2": int64(2)}, serrors.GetFields(err))
+	})
+
+	t.Run("AddStackExtractor takes precedence", func(t *testing.T) {
+		serrors.AddStackExtractor(func(err error) ([]serrors.StackFrame, bool) {
+			if _, ok := err.(*callersError); ok {
+				return []serrors.StackFrame{{File: "custom.go", Func: "custom", Line: 1}}, true
+			}
+			return nil, false
+		})
+		defer serrors.SetStackExtractors(serrors.DefaultStackExtractors()...)
+
+		Equal(t, []serrors.StackFrame{{File: "custom.go", Func: "custom", Line: 1}},
+			serrors.GetStack(newCallersError())[0].StackTrace)
+		skipWithoutStack(t)
+		Equal(t, true, len(serrors.GetStack(newCustomStackTraceError())[0].StackTrace) > 0)
+	})
+
+	t.Run("AddFieldsExtractor takes precedence", func(t *testing.T) {
+		serrors.AddFieldsExtractor(func(err error) ([]serrors.Field, bool) {
+			if _, ok := err.(fieldsError); ok {
+				return []serrors.Field{{Key: "custom", Value: true}}, true
+			}
+			return nil, false
+		})
+		defer serrors.SetFieldsExtractors(serrors.DefaultFieldsExtractors()...)
+
+		Equal(t, map[string]any{"custom": true}, serrors.GetFields(fieldsError{}))
+	})
+
+	t.Run("no extractors", func(t *testing.T) {
+		serrors.SetStackExtractors()
+		serrors.SetFieldsExtractors()
+		defer serrors.SetStackExtractors(serrors.DefaultStackExtractors()...)
+		defer serrors.SetFieldsExtractors(serrors.DefaultFieldsExtractors()...)
+
+		Nil(t, serrors.GetStack(newCallersError())[0].StackTrace)
+		Nil(t, serrors.GetFields(fieldsError{}))
+	})
+}
@@ -31,6 +31,14 @@ func CompareErrorStack(t *testing.T, expected, actual []serrors.ErrorStack) {
 	Equal(t, expectedStack, actualStack)
 }
 
+// skipWithoutStack skips the test when stack traces are disabled by the serrors_without_stack build tag.
+func skipWithoutStack(t *testing.T) {
+	t.Helper()
+	if !stackEnabled {
+		t.Skip("stack traces are disabled by the serrors_without_stack build tag")
+	}
+}
+
 func Equal(t *testing.T, expected, actual any) {
 	if expected == nil && actual == nil {
 		return